@@ -0,0 +1,197 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// ClipByRect returns the part of g that lies within env, as a new Geometry.
+// Polygonal geometries are clipped via Overlay (intersecting with a
+// rectangle built from env); points and lines are clipped directly without
+// going through the overlay machinery, since a simple envelope containment
+// check or segment clip suffices for those lower-dimensional cases.
+func ClipByRect(g Geometry, env Envelope) Geometry {
+	switch g.Type() {
+	case TypePolygon, TypeMultiPolygon:
+		return Overlay(g, rectPolygon(env), OverlayIntersection)
+	case TypePoint:
+		pt := g.AsPoint()
+		if env.Contains(pt.XY()) {
+			return pt
+		}
+		return NewGeometryCollection(nil)
+	case TypeMultiPoint:
+		return clipMultiPointByRect(g.AsMultiPoint(), env)
+	case TypeLineString:
+		return clipMultiLineStringByRect(g.AsLineString().AsMultiLineString(), env)
+	case TypeMultiLineString:
+		return clipMultiLineStringByRect(g.AsMultiLineString(), env)
+	case TypeGeometryCollection:
+		gc := g.AsGeometryCollection()
+		var parts []Geometry
+		gc.walk(func(child Geometry) {
+			clipped := ClipByRect(child, env)
+			if !clipped.IsEmpty() {
+				parts = append(parts, clipped)
+			}
+		})
+		return NewGeometryCollection(parts)
+	default:
+		panic(fmt.Sprintf("ClipByRect: unsupported geometry type %v", g.Type()))
+	}
+}
+
+// rectPolygon builds a Polygon whose single ring is the boundary of env.
+func rectPolygon(env Envelope) Polygon {
+	b := env.box()
+	coords := [][]Coordinates{{
+		{XY: XY{b.MinX, b.MinY}},
+		{XY: XY{b.MaxX, b.MinY}},
+		{XY: XY{b.MaxX, b.MaxY}},
+		{XY: XY{b.MinX, b.MaxY}},
+		{XY: XY{b.MinX, b.MinY}},
+	}}
+	poly, err := NewPolygonC(coords)
+	if err != nil {
+		panic(err)
+	}
+	return poly
+}
+
+func clipMultiPointByRect(mp MultiPoint, env Envelope) Geometry {
+	var coords []Coordinates
+	for i := 0; i < mp.NumPoints(); i++ {
+		pt := mp.PointN(i)
+		if env.Contains(pt.XY()) {
+			coords = append(coords, Coordinates{XY: pt.XY()})
+		}
+	}
+	return NewMultiPointC(coords)
+}
+
+func clipMultiLineStringByRect(mls MultiLineString, env Envelope) Geometry {
+	box := env.box()
+	var coords [][]Coordinates
+	for i := 0; i < mls.NumLineStrings(); i++ {
+		for _, ln := range mls.LineStringN(i).lines {
+			if a, b, ok := clipSegmentToBox(ln.a.XY, ln.b.XY, box); ok {
+				coords = append(coords, []Coordinates{{XY: a}, {XY: b}})
+			}
+		}
+	}
+	if len(coords) == 0 {
+		return NewGeometryCollection(nil)
+	}
+	out, err := NewMultiLineStringC(coords)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// clipSegmentToBox clips the segment a->b against box using the
+// Liang-Barsky algorithm, returning the clipped endpoints and true, or
+// false if none of the segment lies within box.
+func clipSegmentToBox(a, b XY, box Box) (XY, XY, bool) {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	tMin, tMax := 0.0, 1.0
+
+	clipT := func(p, q float64) bool {
+		if p == 0 {
+			return q >= 0
+		}
+		t := q / p
+		if p < 0 {
+			if t > tMax {
+				return false
+			}
+			if t > tMin {
+				tMin = t
+			}
+		} else {
+			if t < tMin {
+				return false
+			}
+			if t < tMax {
+				tMax = t
+			}
+		}
+		return true
+	}
+
+	if clipT(-dx, a.X-box.MinX) && clipT(dx, box.MaxX-a.X) &&
+		clipT(-dy, a.Y-box.MinY) && clipT(dy, box.MaxY-a.Y) {
+		start := XY{a.X + tMin*dx, a.Y + tMin*dy}
+		end := XY{a.X + tMax*dx, a.Y + tMax*dy}
+		return start, end, true
+	}
+	return XY{}, XY{}, false
+}
+
+// splitByGridMaxVertices is the vertex-count threshold above which
+// SplitByGrid subdivides a cell further rather than accepting it as-is.
+const splitByGridMaxVertices = 256
+
+// splitByGridMinCellSize is the smallest cell SplitByGrid will subdivide
+// down to, regardless of how many vertices a cell still contains.
+const splitByGridMinCellSize = 1e-9
+
+// SplitByGrid splits g against a uniform grid of the given cellSize,
+// returning one Geometry per non-empty cell. Cells that still contain more
+// than splitByGridMaxVertices vertices after clipping are adaptively
+// subdivided into quadrants (down to splitByGridMinCellSize) so that no
+// single shard stays expensive to process downstream. This allows a large
+// MultiPolygon/MultiLineString to be sharded for parallel processing, and
+// speeds up subsequent Intersects/Contains queries against the shards since
+// they hit the point-in-polygon and segment-intersection paths with much
+// smaller inputs.
+func SplitByGrid(g Geometry, cellSize float64) []Geometry {
+	env, ok := g.Envelope()
+	if !ok {
+		return nil
+	}
+	b := env.box()
+
+	var out []Geometry
+	minX := math.Floor(b.MinX/cellSize) * cellSize
+	minY := math.Floor(b.MinY/cellSize) * cellSize
+	for x := minX; x < b.MaxX; x += cellSize {
+		for y := minY; y < b.MaxY; y += cellSize {
+			cell := NewEnvelope(XY{x, y}).Extend(XY{x + cellSize, y + cellSize})
+			out = append(out, splitByGridCell(g, cell, cellSize)...)
+		}
+	}
+	return out
+}
+
+func splitByGridCell(g Geometry, cell Envelope, cellSize float64) []Geometry {
+	clipped := ClipByRect(g, cell)
+	if clipped.IsEmpty() {
+		return nil
+	}
+	if countVertices(clipped) <= splitByGridMaxVertices || cellSize <= splitByGridMinCellSize {
+		return []Geometry{clipped}
+	}
+
+	b := cell.box()
+	half := cellSize / 2
+	mx, my := (b.MinX+b.MaxX)/2, (b.MinY+b.MaxY)/2
+	quads := [4]Envelope{
+		NewEnvelope(XY{b.MinX, b.MinY}).Extend(XY{mx, my}),
+		NewEnvelope(XY{mx, b.MinY}).Extend(XY{b.MaxX, my}),
+		NewEnvelope(XY{b.MinX, my}).Extend(XY{mx, b.MaxY}),
+		NewEnvelope(XY{mx, my}).Extend(XY{b.MaxX, b.MaxY}),
+	}
+	var out []Geometry
+	for _, q := range quads {
+		out = append(out, splitByGridCell(clipped, q, half)...)
+	}
+	return out
+}
+
+// countVertices gives an approximate vertex count for g, used by
+// SplitByGrid to decide whether a cell needs further subdivision.
+func countVertices(g Geometry) int {
+	xys, lns := extractXYsAndLines(g)
+	return len(xys) + len(lns) + 1
+}
@@ -1,4 +1,4 @@
-package simplefeatures
+package geom
 
 import (
 	"fmt"
@@ -42,13 +42,62 @@ func orientation(p, q, s XY) threePointOrientation {
 	}
 }
 
-func convexHull(g Geometry) Geometry {
+// HullAlgorithm selects which algorithm ConvexHull uses to build a convex
+// hull.
+type HullAlgorithm int
+
+const (
+	// HullGrahamScan builds the hull via a polar-angle sort followed by
+	// Graham's scan. This is the default algorithm.
+	HullGrahamScan HullAlgorithm = iota
+
+	// HullMonotoneChain builds the hull via Andrew's monotone chain
+	// algorithm, preceded by an Akl-Toussaint pre-filter. It avoids the
+	// polar-angle sort's awkward three-way comparisons for points collinear
+	// with the anchor, and is typically faster on large point clouds.
+	HullMonotoneChain
+)
+
+// ConvexHullOption customises the behaviour of ConvexHull.
+type ConvexHullOption func(*convexHullOptions)
+
+type convexHullOptions struct {
+	algorithm HullAlgorithm
+}
+
+// WithAlgorithm selects the algorithm that ConvexHull uses to build the
+// hull. The default, if this option isn't supplied, is HullGrahamScan.
+func WithAlgorithm(alg HullAlgorithm) ConvexHullOption {
+	return func(o *convexHullOptions) {
+		o.algorithm = alg
+	}
+}
+
+// ConvexHull computes the convex hull of g. It may either be the empty set,
+// a single point, a line, or a closed polygon.
+func ConvexHull(g Geometry, opts ...ConvexHullOption) Geometry {
+	return convexHull(g, opts...)
+}
+
+func convexHull(g Geometry, opts ...ConvexHullOption) Geometry {
 	if g.IsEmpty() {
 		// special case to mirror postgis behaviour
 		return g
 	}
+
+	var o convexHullOptions // defaults to HullGrahamScan
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	pts := g.convexHullPointSet()
-	hull := grahamScan(pts)
+	var hull []XY
+	switch o.algorithm {
+	case HullMonotoneChain:
+		hull = andrewsMonotoneChain(pts)
+	default:
+		hull = grahamScan(pts)
+	}
 	switch len(hull) {
 	case 0:
 		return NewGeometryCollection(nil)
@@ -194,4 +243,141 @@ func ltl(ps []XY) int {
 func distanceSq(p, q XY) Scalar {
 	pSubQ := p.Sub(q)
 	return pSubQ.Dot(pSubQ)
-}
\ No newline at end of file
+}
+
+// andrewsMonotoneChain returns the convex hull of the input points (in the
+// same "either empty, point, line, or closed polygon" shape as grahamScan),
+// using Andrew's monotone chain algorithm. Points are first narrowed down by
+// aklToussaintFilter, then sorted lexicographically by (x, y). The lower
+// hull is built by scanning the sorted points left-to-right, popping the
+// last point added whenever it and the next two points don't make a left
+// turn; the upper hull is built the same way scanning right-to-left. The two
+// chains are then concatenated, dropping the duplicated endpoint each one
+// ends on, and a resulting ring is rotated to start (and end) at its
+// lowest-then-leftmost point, as grahamScan's does.
+func andrewsMonotoneChain(ps []XY) []XY {
+	ps = aklToussaintFilter(ps)
+	if len(ps) <= 2 {
+		return ps
+	}
+
+	sorted := append([]XY(nil), ps...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].X.Equals(sorted[j].X) {
+			return sorted[i].X.LT(sorted[j].X)
+		}
+		return sorted[i].Y.LT(sorted[j].Y)
+	})
+
+	lower := monotoneChainHalf(sorted)
+	upper := monotoneChainHalf(reversedXYs(sorted))
+
+	hull := append(lower[:len(lower)-1], upper[:len(upper)-1]...)
+	if len(hull) >= 3 {
+		// Start and close the ring at its lowest-then-leftmost point,
+		// matching grahamScan's output.
+		start := ltl(hull)
+		hull = append(append([]XY(nil), hull[start:]...), hull[:start]...)
+		hull = append(hull, hull[0])
+	}
+	return hull
+}
+
+// monotoneChainHalf builds one of the two chains (lower or upper, depending
+// on the order pts is supplied in) used by andrewsMonotoneChain.
+func monotoneChainHalf(pts []XY) []XY {
+	var chain pointStack
+	for _, p := range pts {
+		for len(chain) >= 2 && orientation(chain.underTop(), chain.top(), p) != leftTurn {
+			chain.pop()
+		}
+		chain.push(p)
+	}
+	return chain
+}
+
+// reversedXYs returns a new slice containing the elements of ps in reverse order.
+func reversedXYs(ps []XY) []XY {
+	rev := make([]XY, len(ps))
+	for i, p := range ps {
+		rev[len(ps)-1-i] = p
+	}
+	return rev
+}
+
+// aklToussaintFilter discards points of ps that cannot possibly be part of
+// the convex hull. It finds the (up to four) points with the min/max x and
+// min/max y coordinates, forms the convex quadrilateral they bound, and
+// removes every point of ps that falls strictly inside it. On real
+// geographic point clouds, this typically removes the large majority of
+// points before the more expensive sort-based hull construction runs.
+func aklToussaintFilter(ps []XY) []XY {
+	if len(ps) < 4 {
+		return ps
+	}
+
+	extremes := [4]XY{ps[0], ps[0], ps[0], ps[0]} // min-x, min-y, max-x, max-y
+	for _, p := range ps[1:] {
+		if p.X.LT(extremes[0].X) {
+			extremes[0] = p
+		}
+		if p.Y.LT(extremes[1].Y) {
+			extremes[1] = p
+		}
+		if p.X.GT(extremes[2].X) {
+			extremes[2] = p
+		}
+		if p.Y.GT(extremes[3].Y) {
+			extremes[3] = p
+		}
+	}
+
+	quad := dedupeXYs(extremes[:])
+	if len(quad) < 3 {
+		return ps
+	}
+	sortByPolarAngle(quad)
+
+	filtered := make([]XY, 0, len(ps))
+	for _, p := range ps {
+		if !insideConvexPolygon(p, quad) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// insideConvexPolygon reports whether p lies strictly inside the convex
+// polygon whose vertices (in either winding order) are given by poly.
+func insideConvexPolygon(p XY, poly []XY) bool {
+	n := len(poly)
+	var side threePointOrientation
+	for i := 0; i < n; i++ {
+		a, b := poly[i], poly[(i+1)%n]
+		o := orientation(a, b, p)
+		if o == collinear || (side != 0 && o != side) {
+			return false
+		}
+		side = o
+	}
+	return true
+}
+
+// dedupeXYs returns a new slice containing the distinct elements of xys,
+// preserving order of first occurrence.
+func dedupeXYs(xys []XY) []XY {
+	out := make([]XY, 0, len(xys))
+	for _, xy := range xys {
+		seen := false
+		for _, o := range out {
+			if xy.Equals(o) {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			out = append(out, xy)
+		}
+	}
+	return out
+}
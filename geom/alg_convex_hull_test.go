@@ -0,0 +1,59 @@
+package geom_test
+
+import (
+	"testing"
+
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+func TestConvexHull(t *testing.T) {
+	for _, tt := range []struct {
+		wkt  string
+		want string
+	}{
+		{
+			wkt:  "POINT EMPTY",
+			want: "POINT EMPTY",
+		},
+		{
+			wkt:  "MULTIPOINT(0 0)",
+			want: "POINT(0 0)",
+		},
+		{
+			wkt:  "MULTIPOINT(0 0,1 1)",
+			want: "LINESTRING(0 0,1 1)",
+		},
+		{
+			// A square with an extra point in the middle, which must not
+			// appear in the hull.
+			wkt:  "MULTIPOINT(0 0,0 1,1 1,1 0,0.5 0.5)",
+			want: "POLYGON((0 0,1 0,1 1,0 1,0 0))",
+		},
+		{
+			// Collinear points: the hull collapses to the two extremes.
+			wkt:  "MULTIPOINT(0 0,1 0,2 0,3 0)",
+			want: "LINESTRING(0 0,3 0)",
+		},
+	} {
+		t.Run(tt.wkt, func(t *testing.T) {
+			g := geomFromWKT(t, tt.wkt)
+			got := geom.ConvexHull(g)
+			want := geomFromWKT(t, tt.want)
+			expectGeomEq(t, got, want)
+		})
+	}
+}
+
+func TestConvexHullAlgorithmsAgree(t *testing.T) {
+	for _, wkt := range []string{
+		"MULTIPOINT(0 0,0 1,1 1,1 0,0.5 0.5,2 2,-1 3,4 1)",
+		"MULTIPOINT(1 1,2 2,3 3,4 4,5 1)",
+	} {
+		t.Run(wkt, func(t *testing.T) {
+			g := geomFromWKT(t, wkt)
+			grahamScan := geom.ConvexHull(g, geom.WithAlgorithm(geom.HullGrahamScan))
+			monotoneChain := geom.ConvexHull(g, geom.WithAlgorithm(geom.HullMonotoneChain))
+			expectGeomEq(t, grahamScan, monotoneChain)
+		})
+	}
+}
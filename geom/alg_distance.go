@@ -176,3 +176,160 @@ func distBetweenLineAndLine(ln1, ln2 line) float64 {
 	}
 	return minDist
 }
+
+// HausdorffDistance computes the Hausdorff distance between g1 and g2: the
+// greater of the two directed Hausdorff distances between them (each being
+// the maximum, over every point of one geometry, of its minimum distance to
+// the other). Unlike Distance, which finds how close the two geometries'
+// nearest points are, HausdorffDistance characterises how far their shapes
+// can be from matching up.
+//
+// For polygonal inputs, the directed Hausdorff distance from g1 to g2 is
+// always attained at a vertex of g1 (distance-to-a-line-segment being a
+// convex function, its maximum along any edge of g1 falls at one of that
+// edge's endpoints), so it suffices to measure from every vertex of g1 to
+// the nearest point of g2 (vertex or segment) rather than sampling g1's
+// interior.
+func HausdorffDistance(g1, g2 Geometry) (float64, bool) {
+	xys1, lns1 := extractXYsAndLines(g1)
+	xys2, lns2 := extractXYsAndLines(g2)
+	if len(xys1)+len(lns1) == 0 || len(xys2)+len(lns2) == 0 {
+		return 0, false
+	}
+	return math.Max(
+		directedHausdorffDistance(xys1, lns1, xys2, lns2),
+		directedHausdorffDistance(xys2, lns2, xys1, lns1),
+	), true
+}
+
+// directedHausdorffDistance computes the directed Hausdorff distance from
+// the shape made up of (fromXYs, fromLines) to the shape made up of (toXYs,
+// toLines): the maximum, over every vertex of the "from" shape, of its
+// minimum distance to the "to" shape.
+func directedHausdorffDistance(fromXYs []XY, fromLines []line, toXYs []XY, toLines []line) float64 {
+	maxMin := 0.0
+	measureFrom := func(from XY) {
+		minDist := math.Inf(+1)
+		for _, to := range toXYs {
+			minDist = math.Min(minDist, distBetweenXYs(from, to))
+		}
+		for _, ln := range toLines {
+			minDist = math.Min(minDist, distBetweenXYAndLine(from, ln))
+		}
+		maxMin = math.Max(maxMin, minDist)
+	}
+	for _, xy := range fromXYs {
+		measureFrom(xy)
+	}
+	for _, ln := range fromLines {
+		measureFrom(ln.a)
+		measureFrom(ln.b)
+	}
+	return maxMin
+}
+
+// DiscreteHausdorffDistance computes the discrete Hausdorff distance between
+// g1 and g2, following the same semantics as JTS/PostGIS's
+// ST_HausdorffDistance: the greater of the two directed Hausdorff distances
+// between g1's and g2's vertex sets (i.e. only vertices are compared, unlike
+// HausdorffDistance which also measures against the interior of line
+// segments).
+//
+// If densifyFrac is greater than 0, every line segment making up g1 and g2
+// is first densified by inserting extra vertices, so that no piece is
+// longer than densifyFrac times the segment's own length; this tightens the
+// discrete approximation towards the continuous Hausdorff distance, at the
+// cost of more vertices to compare. densifyFrac should be in the range (0,
+// 1]; 0 disables densification.
+func DiscreteHausdorffDistance(g1, g2 Geometry, densifyFrac float64) (float64, bool) {
+	xys1, lns1 := extractXYsAndLines(g1)
+	xys2, lns2 := extractXYsAndLines(g2)
+	verts1 := vertexSet(xys1, lns1, densifyFrac)
+	verts2 := vertexSet(xys2, lns2, densifyFrac)
+	if len(verts1) == 0 || len(verts2) == 0 {
+		return 0, false
+	}
+	return math.Max(
+		directedHausdorffDistance(verts1, nil, verts2, nil),
+		directedHausdorffDistance(verts2, nil, verts1, nil),
+	), true
+}
+
+// vertexSet flattens xys and the endpoints of lns into a single slice of
+// vertices, densifying each line first (see DiscreteHausdorffDistance) if
+// densifyFrac is greater than 0.
+func vertexSet(xys []XY, lns []line, densifyFrac float64) []XY {
+	verts := append([]XY(nil), xys...)
+	for _, ln := range lns {
+		if densifyFrac <= 0 {
+			verts = append(verts, ln.a, ln.b)
+			continue
+		}
+		n := int(math.Ceil(1 / densifyFrac))
+		if n < 1 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			t := float64(i) / float64(n)
+			verts = append(verts, XY{
+				X: ln.a.X + t*(ln.b.X-ln.a.X),
+				Y: ln.a.Y + t*(ln.b.Y-ln.a.Y),
+			})
+		}
+		verts = append(verts, ln.b)
+	}
+	return verts
+}
+
+// DiscreteFrechetDistance computes the discrete Fréchet distance between ls1
+// and ls2, using the dynamic-programming recurrence of Eiter and Mannila:
+// with P = p1..pn and Q = q1..qm the vertices of ls1 and ls2 respectively,
+//
+//	ca[1][1] = d(p1, q1)
+//	ca[i][j] = max(min(ca[i-1][j], ca[i-1][j-1], ca[i][j-1]), d(pi, qj))
+//
+// and the result is ca[n][m]. Only two rows of the ca table are kept at a
+// time (rather than the full n*m matrix), and the shorter of ls1/ls2's
+// vertex sequences is used as the row dimension, so memory use is
+// O(min(n, m)).
+func DiscreteFrechetDistance(ls1, ls2 LineString) (float64, bool) {
+	p := xysFromSequence(ls1.Coordinates())
+	q := xysFromSequence(ls2.Coordinates())
+	if len(p) == 0 || len(q) == 0 {
+		return 0, false
+	}
+	if len(p) < len(q) {
+		p, q = q, p
+	}
+	n, m := len(p), len(q)
+
+	prev := make([]float64, m)
+	curr := make([]float64, m)
+	for j := 0; j < m; j++ {
+		if j == 0 {
+			prev[j] = distBetweenXYs(p[0], q[0])
+		} else {
+			prev[j] = math.Max(prev[j-1], distBetweenXYs(p[0], q[j]))
+		}
+	}
+	for i := 1; i < n; i++ {
+		curr[0] = math.Max(prev[0], distBetweenXYs(p[i], q[0]))
+		for j := 1; j < m; j++ {
+			curr[j] = math.Max(
+				math.Min(prev[j], math.Min(prev[j-1], curr[j-1])),
+				distBetweenXYs(p[i], q[j]),
+			)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[m-1], true
+}
+
+// xysFromSequence collects every vertex of seq into a slice.
+func xysFromSequence(seq Sequence) []XY {
+	xys := make([]XY, seq.Length())
+	for i := range xys {
+		xys[i] = seq.GetXY(i)
+	}
+	return xys
+}
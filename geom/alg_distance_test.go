@@ -0,0 +1,123 @@
+package geom_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+func expectFloatEq(t *testing.T, got, want float64) {
+	t.Helper()
+	const epsilon = 1e-9
+	if math.Abs(got-want) > epsilon {
+		t.Errorf("got: %v want: %v", got, want)
+	}
+}
+
+func TestHausdorffDistance(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		wkt1, wkt2 string
+		want       float64
+	}{
+		{
+			name: "coincident points",
+			wkt1: "POINT(0 0)",
+			wkt2: "POINT(0 0)",
+			want: 0,
+		},
+		{
+			name: "two points",
+			wkt1: "POINT(0 0)",
+			wkt2: "POINT(3 4)",
+			want: 5,
+		},
+		{
+			name: "point and line, symmetric regardless of operand order",
+			wkt1: "POINT(0 2)",
+			wkt2: "LINESTRING(0 0,10 0)",
+			// The furthest point of the line from (0, 2) is one of its
+			// endpoints, 10 away horizontally and 2 vertically.
+			want: math.Hypot(10, 2),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g1 := geomFromWKT(t, tt.wkt1)
+			g2 := geomFromWKT(t, tt.wkt2)
+
+			got, ok := geom.HausdorffDistance(g1, g2)
+			if !ok {
+				t.Fatalf("HausdorffDistance reported no result")
+			}
+			expectFloatEq(t, got, tt.want)
+
+			gotSwapped, ok := geom.HausdorffDistance(g2, g1)
+			if !ok {
+				t.Fatalf("HausdorffDistance reported no result")
+			}
+			expectFloatEq(t, gotSwapped, tt.want)
+		})
+	}
+}
+
+func TestHausdorffDistanceEmptyInput(t *testing.T) {
+	empty := geomFromWKT(t, "POINT EMPTY")
+	pt := geomFromWKT(t, "POINT(0 0)")
+	if _, ok := geom.HausdorffDistance(empty, pt); ok {
+		t.Errorf("HausdorffDistance against an empty geometry should report no result")
+	}
+}
+
+func TestDiscreteHausdorffDistanceDensify(t *testing.T) {
+	// Two parallel lines, offset by 1 in Y, where one line's only vertices
+	// are its endpoints. Without densification, the discrete Hausdorff
+	// distance (vertex-to-vertex/segment) and the continuous one agree here
+	// (1), so this mostly checks that supplying densifyFrac doesn't change
+	// a result that's already exact for a pair of parallel segments.
+	ls1 := geomFromWKT(t, "LINESTRING(0 0,10 0)")
+	ls2 := geomFromWKT(t, "LINESTRING(0 1,10 1)")
+
+	got, ok := geom.DiscreteHausdorffDistance(ls1, ls2, 0)
+	if !ok {
+		t.Fatalf("DiscreteHausdorffDistance reported no result")
+	}
+	expectFloatEq(t, got, 1)
+
+	gotDensified, ok := geom.DiscreteHausdorffDistance(ls1, ls2, 0.1)
+	if !ok {
+		t.Fatalf("DiscreteHausdorffDistance reported no result")
+	}
+	expectFloatEq(t, gotDensified, 1)
+}
+
+func TestDiscreteFrechetDistance(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		wkt1, wkt2 string
+		want       float64
+	}{
+		{
+			name: "identical lines",
+			wkt1: "LINESTRING(0 0,1 0,2 0)",
+			wkt2: "LINESTRING(0 0,1 0,2 0)",
+			want: 0,
+		},
+		{
+			name: "parallel lines offset by 1",
+			wkt1: "LINESTRING(0 0,1 0,2 0)",
+			wkt2: "LINESTRING(0 1,1 1,2 1)",
+			want: 1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ls1 := geomFromWKT(t, tt.wkt1).AsLineString()
+			ls2 := geomFromWKT(t, tt.wkt2).AsLineString()
+			got, ok := geom.DiscreteFrechetDistance(ls1, ls2)
+			if !ok {
+				t.Fatalf("DiscreteFrechetDistance reported no result")
+			}
+			expectFloatEq(t, got, tt.want)
+		})
+	}
+}
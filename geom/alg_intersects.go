@@ -2,8 +2,6 @@ package geom
 
 import (
 	"fmt"
-	"math"
-	"sort"
 )
 
 func hasIntersection(g1, g2 Geometry) bool {
@@ -217,91 +215,12 @@ func hasIntersectionMultiPointWithMultiLineString(mp MultiPoint, mls MultiLineSt
 	return false
 }
 
+// hasIntersectionMultiLineStringWithMultiLineString is a thin wrapper around
+// the Bentley-Ottmann sweep in hasLineSegmentIntersection: it returns as soon
+// as the sweep reports the first crossing, rather than enumerating every
+// intersection point like LineSegmentIntersections does.
 func hasIntersectionMultiLineStringWithMultiLineString(mls1, mls2 MultiLineString) bool {
-	// A Sweep-Line-Algorithm approach is used to reduce the number of raw line
-	// segment intersection tests that must be performed. A vertical sweep line
-	// is swept across the plane from left to right. Two 'active' sets of
-	// segments are maintained for each multi line string, corresponding to the
-	// segments that intersect with the sweep line. Only segments in the active
-	// sets need to be considered when checking to see if the multi line
-	// strings intersect with each other.
-
-	type side struct {
-		mls         MultiLineString
-		unprocessed []Line
-		active      lineHeap
-		newSegments []Line
-	}
-	var sides [2]*side
-	less := func(a, b Line) bool {
-		return a.EndPoint().XY().X < b.EndPoint().XY().X
-	}
-	sides[0] = &side{mls: mls1, active: lineHeap{less: less}}
-	sides[1] = &side{mls: mls2, active: lineHeap{less: less}}
-
-	// Create a list of line segments from each MultiLineString, in ascending
-	// order by X coordinate.
-	for _, side := range sides {
-		var n int
-		for _, ls := range side.mls.lines {
-			n += ls.NumPoints() - 1
-		}
-		side.unprocessed = make([]Line, 0, n)
-		for _, ls := range side.mls.lines {
-			for _, ln := range ls.lines {
-				if ln.StartPoint().XY().X > ln.EndPoint().XY().X {
-					// TODO: Use ST_Reverse
-					ln.a, ln.b = ln.b, ln.a
-				}
-				side.unprocessed = append(side.unprocessed, ln)
-			}
-		}
-		sort.Slice(side.unprocessed, func(i, j int) bool {
-			ix := side.unprocessed[i].StartPoint().XY().X
-			jx := side.unprocessed[j].StartPoint().XY().X
-			return ix < jx
-		})
-	}
-
-	for len(sides[0].unprocessed)+len(sides[1].unprocessed) > 0 {
-		// Calculate the X coordinate of the next line segment(s) that will be
-		// processed when sweeping left to right.
-		sweepX := math.Inf(+1)
-		for _, side := range sides {
-			if len(side.unprocessed) > 0 {
-				sweepX = math.Min(sweepX, side.unprocessed[0].StartPoint().XY().X)
-			}
-		}
-
-		// Update the active line segment sets by throwing away any line
-		// segments that can no longer possibly intersect with any unprocessed
-		// line segments, and adding any new line segments to the active sets.
-		for _, side := range sides {
-			for !side.active.empty() && side.active.peek().EndPoint().XY().X < sweepX {
-				side.active.pop()
-			}
-			side.newSegments = side.newSegments[:0]
-			for len(side.unprocessed) > 0 && side.unprocessed[0].StartPoint().XY().X == sweepX {
-				side.newSegments = append(side.newSegments, side.unprocessed[0])
-				side.active.push(side.unprocessed[0])
-				side.unprocessed = side.unprocessed[1:]
-			}
-		}
-
-		// Check for intersection between any new line segments, and segments
-		// in the opposing active set.
-		for i, side := range sides {
-			other := sides[1-i]
-			for _, checkLine := range side.newSegments {
-				for _, ln := range other.active.data {
-					if hasIntersectionLineWithLine(ln, checkLine) {
-						return true
-					}
-				}
-			}
-		}
-	}
-	return false
+	return hasLineSegmentIntersection(mls1, mls2)
 }
 
 func hasIntersectionMultiLineStringWithMultiPolygon(mls MultiLineString, mp MultiPolygon) bool {
@@ -416,12 +335,16 @@ func hasIntersectionPointWithPolygon(pt Point, p Polygon) bool {
 }
 
 func hasIntersectionMultiPointWithPolygon(mp MultiPoint, p Polygon) bool {
-	// Speed is O(n*m), n is the number of points, m is the number of holes in the polygon.
-	n := mp.NumPoints()
+	// Build an R-tree over p's ring edges once, then query it for each
+	// point. This brings each point-in-polygon test down from O(m) to
+	// O(log m), where m is the total number of ring edges.
+	edges := ringEdges(p)
+	index := p.SpatialIndex()
 
+	n := mp.NumPoints()
 	for i := 0; i < n; i++ {
 		pt := mp.PointN(i)
-		if hasIntersectionPointWithPolygon(pt, p) {
+		if hasIntersectionPointWithPolygonIndexed(pt.XY(), p, edges, index) {
 			return true
 		}
 	}
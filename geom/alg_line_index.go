@@ -0,0 +1,114 @@
+package geom
+
+import (
+	"math"
+	"sort"
+
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+// index bulk-loads an R-tree over the envelopes of mls's child LineStrings,
+// so that Nearest, NearestK, and LineStringsInBBox only need to consider
+// candidates whose envelope is a plausible match, rather than scanning
+// every LineString. Unlike MultiPoint's index, this one is rebuilt on every
+// call rather than cached: a LineString's envelope is only a bound on its
+// true shape (not the shape itself, as a point's coordinates are), so
+// candidates found this way still need verifying against the actual
+// geometry.
+func (mls MultiLineString) index() *rtree.RTree {
+	var items []rtree.BulkItem
+	for i := 0; i < mls.NumLineStrings(); i++ {
+		env, ok := mls.LineStringN(i).Envelope()
+		if !ok {
+			continue
+		}
+		items = append(items, rtree.BulkItem{Box: env.box(), RecordID: i})
+	}
+	return rtree.BulkLoad(items)
+}
+
+// Nearest returns the LineString in mls closest to pt, along with true. If
+// mls has no non-empty LineStrings, it returns the zero LineString and
+// false.
+//
+// Candidates are visited in order of increasing envelope distance from pt;
+// the search stops as soon as a candidate's envelope is already further
+// away than the best true distance found so far, since every later
+// candidate can only be at least as far.
+func (mls MultiLineString) Nearest(pt Point) (LineString, bool) {
+	ptEnv := NewEnvelope(pt.XY())
+	best := math.Inf(+1)
+	bestIdx := -1
+
+	mls.index().PrioritySearch(ptEnv.box(), func(recordID int) error {
+		childEnv, _ := mls.LineStringN(recordID).Envelope()
+		if childEnv.Distance(ptEnv) > best {
+			return rtree.Stop
+		}
+		if d, ok := distance(pt, mls.LineStringN(recordID)); ok && d < best {
+			best = d
+			bestIdx = recordID
+		}
+		return nil
+	})
+
+	if bestIdx < 0 {
+		return LineString{}, false
+	}
+	return mls.LineStringN(bestIdx), true
+}
+
+// NearestK returns up to k of the LineStrings in mls closest to pt, in
+// increasing order of distance from pt.
+func (mls MultiLineString) NearestK(pt Point, k int) []LineString {
+	if k <= 0 {
+		return nil
+	}
+
+	type candidate struct {
+		idx  int
+		dist float64
+	}
+	var best []candidate // kept sorted by dist, capped at length k
+
+	ptEnv := NewEnvelope(pt.XY())
+	mls.index().PrioritySearch(ptEnv.box(), func(recordID int) error {
+		childEnv, _ := mls.LineStringN(recordID).Envelope()
+		if len(best) == k && childEnv.Distance(ptEnv) > best[len(best)-1].dist {
+			return rtree.Stop
+		}
+		d, ok := distance(pt, mls.LineStringN(recordID))
+		if !ok {
+			return nil
+		}
+		i := sort.Search(len(best), func(i int) bool { return best[i].dist >= d })
+		best = append(best, candidate{})
+		copy(best[i+1:], best[i:])
+		best[i] = candidate{idx: recordID, dist: d}
+		if len(best) > k {
+			best = best[:k]
+		}
+		return nil
+	})
+
+	results := make([]LineString, len(best))
+	for i, c := range best {
+		results[i] = mls.LineStringN(c.idx)
+	}
+	return results
+}
+
+// LineStringsInBBox returns every LineString in mls whose envelope
+// intersects env.
+func (mls MultiLineString) LineStringsInBBox(env Envelope) []LineString {
+	var results []LineString
+	mls.index().PrioritySearch(env.box(), func(recordID int) error {
+		childEnv, _ := mls.LineStringN(recordID).Envelope()
+		if childEnv.Distance(env) > 0 {
+			return rtree.Stop
+		}
+		results = append(results, mls.LineStringN(recordID))
+		return nil
+	})
+	return results
+}
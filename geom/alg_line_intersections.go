@@ -0,0 +1,312 @@
+package geom
+
+import (
+	"container/heap"
+)
+
+// LineSegmentIntersections finds every point at which a segment from mls1
+// crosses or touches a segment from mls2. It uses a Bentley-Ottmann sweep: a
+// vertical line is swept from left to right across the plane, driven by an
+// event queue ordered by (x, y). Three kinds of events are processed:
+//
+//   - A start event, raised when the sweep line reaches the first endpoint of
+//     a segment. The segment is inserted into the status (the set of
+//     segments currently crossing the sweep line, ordered by their
+//     y-coordinate at the sweep line's x position) and is tested against its
+//     immediate neighbours in the status.
+//   - An end event, raised when the sweep line reaches the last endpoint of a
+//     segment. The segment is removed from the status, and its former
+//     neighbours (which are now adjacent to each other) are tested against
+//     each other.
+//   - An intersection event, raised when two segments are found to cross.
+//     The two segments swap positions in the status (since their relative
+//     y-ordering flips as the sweep line passes the crossing point), and the
+//     new neighbour pairs introduced by the swap are tested.
+//
+// Every newly discovered intersection point (including one at the current
+// event point) is pushed onto the event queue so that it is processed in its
+// turn. The event queue is a binary heap, but the status is a plain slice
+// that is searched and updated linearly, so each event costs O(n) and the
+// running time is O((n+k) n), where k is the number of reported
+// intersection points.
+func LineSegmentIntersections(mls1, mls2 MultiLineString) []Point {
+	sweep := newLineSweep(mls1, mls2)
+	sweep.run()
+	pts := make([]Point, 0, len(sweep.found))
+	for xy := range sweep.found {
+		pts = append(pts, NewPoint(xy))
+	}
+	return pts
+}
+
+// hasLineSegmentIntersection is the boolean-fast-path variant of
+// LineSegmentIntersections: it stops as soon as the first intersection is
+// found, rather than enumerating every crossing point.
+func hasLineSegmentIntersection(mls1, mls2 MultiLineString) bool {
+	sweep := newLineSweep(mls1, mls2)
+	sweep.stopOnFirst = true
+	sweep.run()
+	return len(sweep.found) > 0
+}
+
+// sweepSide identifies which of the two input MultiLineStrings a segment
+// event belongs to.
+type sweepSide int
+
+const (
+	sweepSideA sweepSide = iota
+	sweepSideB
+)
+
+type sweepEventKind int
+
+const (
+	sweepEventStart sweepEventKind = iota
+	sweepEventEnd
+	sweepEventIntersection
+)
+
+// sweepSegment is a single line segment taken from one of the two input
+// MultiLineStrings, normalised so that its left endpoint comes first (or, for
+// vertical segments, its lower endpoint).
+type sweepSegment struct {
+	side sweepSide
+	ln   Line
+}
+
+func (s sweepSegment) left() XY  { return s.ln.a.XY }
+func (s sweepSegment) right() XY { return s.ln.b.XY }
+
+// yAtX gives the y-coordinate of the segment at the given x (the segment
+// must be vertical or must span x).
+func (s sweepSegment) yAtX(x float64) float64 {
+	a, b := s.left(), s.right()
+	if a.X == b.X {
+		return a.Y
+	}
+	t := (x - a.X) / (b.X - a.X)
+	return a.Y + t*(b.Y-a.Y)
+}
+
+type sweepEvent struct {
+	x, y     float64
+	kind     sweepEventKind
+	segs     [2]int // indexes into sweep.segs; segs[1] is only used for intersection events
+}
+
+// sweepEventQueue is a min-heap of events ordered by (x, y), and secondarily
+// by kind so that start events at a point are processed before end events,
+// which are processed before intersection events.
+type sweepEventQueue []sweepEvent
+
+func (q sweepEventQueue) Len() int { return len(q) }
+func (q sweepEventQueue) Less(i, j int) bool {
+	if q[i].x != q[j].x {
+		return q[i].x < q[j].x
+	}
+	if q[i].y != q[j].y {
+		return q[i].y < q[j].y
+	}
+	return q[i].kind < q[j].kind
+}
+func (q sweepEventQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *sweepEventQueue) Push(x interface{}) { *q = append(*q, x.(sweepEvent)) }
+func (q *sweepEventQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	ev := old[n-1]
+	*q = old[:n-1]
+	return ev
+}
+
+// lineSweep holds the working state of a single Bentley-Ottmann pass.
+type lineSweep struct {
+	segs        []sweepSegment
+	queue       sweepEventQueue
+	status      []int // indexes into segs, ordered by y at the current sweep x
+	found       map[XY]struct{}
+	stopOnFirst bool
+}
+
+func newLineSweep(mls1, mls2 MultiLineString) *lineSweep {
+	sweep := &lineSweep{found: make(map[XY]struct{})}
+	sweep.addSegments(mls1, sweepSideA)
+	sweep.addSegments(mls2, sweepSideB)
+	heap.Init(&sweep.queue)
+	return sweep
+}
+
+func (s *lineSweep) addSegments(mls MultiLineString, side sweepSide) {
+	for i := 0; i < mls.NumLineStrings(); i++ {
+		ls := mls.LineStringN(i)
+		for _, ln := range ls.lines {
+			startXY, endXY := ln.StartPoint().XY(), ln.EndPoint().XY()
+			if startXY.X > endXY.X || (startXY.X == endXY.X && startXY.Y > endXY.Y) {
+				ln.a, ln.b = ln.b, ln.a
+			}
+			idx := len(s.segs)
+			s.segs = append(s.segs, sweepSegment{side: side, ln: ln})
+			a, b := ln.a.XY, ln.b.XY
+			heap.Push(&s.queue, sweepEvent{
+				x: a.X, y: a.Y,
+				kind: sweepEventStart, segs: [2]int{idx, idx},
+			})
+			heap.Push(&s.queue, sweepEvent{
+				x: b.X, y: b.Y,
+				kind: sweepEventEnd, segs: [2]int{idx, idx},
+			})
+		}
+	}
+}
+
+func (s *lineSweep) run() {
+	for s.queue.Len() > 0 {
+		if s.stopOnFirst && len(s.found) > 0 {
+			return
+		}
+		ev := heap.Pop(&s.queue).(sweepEvent)
+		switch ev.kind {
+		case sweepEventStart:
+			s.handleStart(ev)
+		case sweepEventEnd:
+			s.handleEnd(ev)
+		case sweepEventIntersection:
+			s.handleIntersection(ev)
+		}
+	}
+}
+
+// statusIndexOf finds the position of segIdx within the status slice.
+func (s *lineSweep) statusIndexOf(segIdx int) int {
+	for i, idx := range s.status {
+		if idx == segIdx {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *lineSweep) handleStart(ev sweepEvent) {
+	segIdx := ev.segs[0]
+	pos := s.insertIntoStatus(segIdx, ev.x)
+	if pos > 0 {
+		s.testPair(s.status[pos-1], segIdx)
+	}
+	if pos < len(s.status)-1 {
+		s.testPair(segIdx, s.status[pos+1])
+	}
+}
+
+func (s *lineSweep) handleEnd(ev sweepEvent) {
+	segIdx := ev.segs[0]
+	pos := s.statusIndexOf(segIdx)
+	if pos < 0 {
+		return
+	}
+	var upper, lower int
+	haveUpper := pos > 0
+	haveLower := pos < len(s.status)-1
+	if haveUpper {
+		upper = s.status[pos-1]
+	}
+	if haveLower {
+		lower = s.status[pos+1]
+	}
+	s.status = append(s.status[:pos], s.status[pos+1:]...)
+	if haveUpper && haveLower {
+		s.testPair(upper, lower)
+	}
+}
+
+func (s *lineSweep) handleIntersection(ev sweepEvent) {
+	i, j := s.statusIndexOf(ev.segs[0]), s.statusIndexOf(ev.segs[1])
+	if i < 0 || j < 0 {
+		return
+	}
+	if i > j {
+		i, j = j, i
+	}
+	// The two segments swap order in the status, since their relative
+	// y-ordering flips as the sweep line passes the intersection point.
+	s.status[i], s.status[j] = s.status[j], s.status[i]
+	if i > 0 {
+		s.testPair(s.status[i-1], s.status[i])
+	}
+	if j < len(s.status)-1 {
+		s.testPair(s.status[j], s.status[j+1])
+	}
+}
+
+// insertIntoStatus inserts segIdx into the status slice, keeping it ordered
+// by y-coordinate at x, and returns the position it was inserted at.
+func (s *lineSweep) insertIntoStatus(segIdx int, x float64) int {
+	y := s.segs[segIdx].yAtX(x)
+	pos := len(s.status)
+	for i, idx := range s.status {
+		if s.segs[idx].yAtX(x) > y {
+			pos = i
+			break
+		}
+	}
+	s.status = append(s.status, 0)
+	copy(s.status[pos+1:], s.status[pos:])
+	s.status[pos] = segIdx
+	return pos
+}
+
+// testPair checks a pair of segments (adjacent in the status) for an
+// intersection, recording each crossing point not already found and
+// scheduling an intersection event for it, wherever it lies relative to the
+// current sweep position.
+func (s *lineSweep) testPair(i, j int) {
+	segA, segB := s.segs[i], s.segs[j]
+	if segA.side == segB.side {
+		// Only cross-MultiLineString intersections are reported.
+		return
+	}
+	if !hasIntersectionLineWithLine(segA.ln, segB.ln) {
+		return
+	}
+	for _, xy := range lineIntersectionPoints(segA.ln, segB.ln) {
+		if _, ok := s.found[xy]; ok {
+			continue
+		}
+		s.found[xy] = struct{}{}
+		heap.Push(&s.queue, sweepEvent{
+			x: xy.X, y: xy.Y,
+			kind: sweepEventIntersection, segs: [2]int{i, j},
+		})
+	}
+}
+
+// lineIntersectionPoints returns the point(s) at which two line segments
+// intersect. Two points are returned for a collinear overlap (the endpoints
+// of the overlap), and a single point otherwise.
+func lineIntersectionPoints(n1, n2 Line) []XY {
+	a, b := n1.a.XY, n1.b.XY
+	c, d := n2.a.XY, n2.b.XY
+
+	o1 := orientation(a, b, c)
+	o2 := orientation(a, b, d)
+
+	if o1 == collinear && o2 == collinear {
+		// Collinear overlap: report the two interior endpoints of the
+		// overlapping region.
+		abcd := [4]XY{a, b, c, d}
+		pts := abcd[:]
+		rth := rightmostThenHighestIndex(pts)
+		pts = append(pts[:rth], pts[rth+1:]...)
+		ltl := leftmostThenLowestIndex(pts)
+		pts = append(pts[:ltl], pts[ltl+1:]...)
+		if pts[0].Equals(pts[1]) {
+			return []XY{pts[0]}
+		}
+		return []XY{pts[0], pts[1]}
+	}
+
+	// Proper or touching crossing: solve for the single intersection point.
+	d1 := b.Sub(a)
+	d2 := d.Sub(c)
+	t := c.Sub(a).Cross(d2) / d1.Cross(d2)
+	return []XY{a.Add(d1.Scale(t))}
+}
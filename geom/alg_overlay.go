@@ -0,0 +1,374 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+)
+
+// OverlayOp identifies a boolean set operation to be carried out by Overlay.
+type OverlayOp int
+
+const (
+	// OverlayUnion computes the set union of the two operands.
+	OverlayUnion OverlayOp = iota
+	// OverlayIntersection computes the set intersection of the two operands.
+	OverlayIntersection
+	// OverlayDifference computes the set difference of the two operands
+	// (the parts of the first operand that do not lie in the second).
+	OverlayDifference
+	// OverlaySymmetricDifference computes the set symmetric difference of
+	// the two operands.
+	OverlaySymmetricDifference
+)
+
+// Overlay computes the boolean set operation op between g1 and g2, returning
+// the result as a new Geometry. Currently only Polygon and MultiPolygon
+// operands are supported; other combinations panic.
+//
+// The boundaries of g1 and g2 are noded against each other at every crossing
+// point found by the Bentley-Ottmann sweep in LineSegmentIntersections, and
+// each ring is split into maximal sub-segments between consecutive noded
+// points. Every sub-segment is then classified by testing a representative
+// point on it against the *other* operand with pointRingSide; which classes
+// survive is determined by op. The surviving sub-segments are rewoven into
+// closed rings (distinguishing shells from holes by their signed area) to
+// produce the output Polygon or MultiPolygon.
+func Overlay(g1, g2 Geometry, op OverlayOp) Geometry {
+	mp1 := asOverlayMultiPolygon(g1)
+	mp2 := asOverlayMultiPolygon(g2)
+	return multiPolygonOverlay(mp1, mp2, op)
+}
+
+func asOverlayMultiPolygon(g Geometry) MultiPolygon {
+	switch g.Type() {
+	case TypePolygon:
+		return g.AsPolygon().AsMultiPolygon()
+	case TypeMultiPolygon:
+		return g.AsMultiPolygon()
+	default:
+		panic(fmt.Sprintf("Overlay: unsupported geometry type %v", g.Type()))
+	}
+}
+
+// Union returns the geometry representing the set union of p and other.
+//
+// This delegates to the package-level Union (the DCEL-based overlay) rather
+// than Overlay's ring-classification algorithm, so that it always agrees
+// with geom.Union(p, other) on the same inputs; p and other are always
+// Polygon/MultiPolygon family, so the only way Union can return an error
+// here is a bug in the overlay machinery itself.
+func (p Polygon) Union(other Polygon) Geometry {
+	g, err := Union(p, other)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Difference returns the geometry representing the parts of p that do not
+// lie in other. See the Union method for why this delegates to the
+// package-level Difference rather than Overlay.
+func (p Polygon) Difference(other Polygon) Geometry {
+	g, err := Difference(p, other)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// SymmetricDifference returns the geometry representing the set symmetric
+// difference of p and other. See the Union method for why this delegates to
+// the package-level SymmetricDifference rather than Overlay.
+func (p Polygon) SymmetricDifference(other Polygon) Geometry {
+	g, err := SymmetricDifference(p, other)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+func multiPolygonOverlay(mp1, mp2 MultiPolygon, op OverlayOp) Geometry {
+	seqs1 := multiPolygonRingSeqs(mp1)
+	seqs2 := multiPolygonRingSeqs(mp2)
+
+	crossings := LineSegmentIntersections(ringSeqsToMultiLineString(seqs1), ringSeqsToMultiLineString(seqs2))
+	interactions := make(map[XY]struct{})
+	for _, pt := range crossings {
+		interactions[pt.XY()] = struct{}{}
+	}
+	for _, seq := range seqs1 {
+		addRingVerticesToInteractions(seq, interactions)
+	}
+	for _, seq := range seqs2 {
+		addRingVerticesToInteractions(seq, interactions)
+	}
+
+	var chains []*overlayChain
+	keepOutside := func(inside bool) bool { return !inside }
+	keepInside := func(inside bool) bool { return inside }
+	switch op {
+	case OverlayUnion:
+		chains = append(chains, classifySegments(seqs1, interactions, mp2, keepOutside, false)...)
+		chains = append(chains, classifySegments(seqs2, interactions, mp1, keepOutside, false)...)
+	case OverlayIntersection:
+		chains = append(chains, classifySegments(seqs1, interactions, mp2, keepInside, false)...)
+		chains = append(chains, classifySegments(seqs2, interactions, mp1, keepInside, false)...)
+	case OverlayDifference:
+		chains = append(chains, classifySegments(seqs1, interactions, mp2, keepOutside, false)...)
+		chains = append(chains, classifySegments(seqs2, interactions, mp1, keepInside, true)...)
+	case OverlaySymmetricDifference:
+		chains = append(chains, classifySegments(seqs1, interactions, mp2, keepOutside, false)...)
+		chains = append(chains, classifySegments(seqs1, interactions, mp2, keepInside, true)...)
+		chains = append(chains, classifySegments(seqs2, interactions, mp1, keepOutside, false)...)
+		chains = append(chains, classifySegments(seqs2, interactions, mp1, keepInside, true)...)
+	default:
+		panic(fmt.Sprintf("Overlay: unknown op %v", op))
+	}
+
+	rings := assembleOverlayRings(chains)
+	if len(rings) == 0 {
+		return NewGeometryCollection(nil)
+	}
+	return multiPolygonOrPolygon(buildMultiPolygonFromRings(rings))
+}
+
+func addRingVerticesToInteractions(seq Sequence, interactions map[XY]struct{}) {
+	for i := 0; i < seq.Length(); i++ {
+		interactions[seq.GetXY(i)] = struct{}{}
+	}
+}
+
+func multiPolygonRingSeqs(mp MultiPolygon) []Sequence {
+	var seqs []Sequence
+	for i := 0; i < mp.NumPolygons(); i++ {
+		poly := mp.PolygonN(i)
+		seqs = append(seqs, poly.ExteriorRing().Coordinates())
+		for j := 0; j < poly.NumInteriorRings(); j++ {
+			seqs = append(seqs, poly.InteriorRingN(j).Coordinates())
+		}
+	}
+	return seqs
+}
+
+func ringSeqsToMultiLineString(seqs []Sequence) MultiLineString {
+	coords := make([][]Coordinates, len(seqs))
+	for i, seq := range seqs {
+		cs := make([]Coordinates, seq.Length())
+		for j := range cs {
+			cs[j] = Coordinates{XY: seq.GetXY(j)}
+		}
+		coords[i] = cs
+	}
+	mls, err := NewMultiLineStringC(coords)
+	if err != nil {
+		panic(err)
+	}
+	return mls
+}
+
+// overlayChain is a maximal run of ring vertices between two noded
+// (interaction) points, tagged with whether it has already been consumed
+// while reweaving output rings.
+type overlayChain struct {
+	pts  []XY
+	used bool
+}
+
+// classifySegments splits each ring in seqs at the points in interactions,
+// and keeps the resulting sub-segments for which keep(inside) is true, where
+// inside indicates whether the sub-segment's midpoint lies within other.
+// Kept sub-segments are reversed when reverse is true (used to flip a ring's
+// winding direction, e.g. when it becomes a hole of the opposite operand).
+func classifySegments(
+	seqs []Sequence,
+	interactions map[XY]struct{},
+	other MultiPolygon,
+	keep func(inside bool) bool,
+	reverse bool,
+) []*overlayChain {
+	var chains []*overlayChain
+	for _, seq := range seqs {
+		forEachNonInteractingSegment(seq, interactions, OverlayOptions{}, func(segment []XY) {
+			a, b := segment[0], segment[len(segment)-1]
+			mid := XY{(a.X + b.X) / 2, (a.Y + b.Y) / 2}
+			if !keep(multiPolygonContainsPoint(other, mid)) {
+				return
+			}
+			pts := append([]XY(nil), segment...)
+			if reverse {
+				pts = reverseXYs(pts)
+			}
+			chains = append(chains, &overlayChain{pts: pts})
+		})
+	}
+	return chains
+}
+
+// multiPolygonContainsPoint reports whether pt lies in the interior of mp,
+// using the same exterior/hole ring classification as
+// hasIntersectionPointWithPolygon.
+func multiPolygonContainsPoint(mp MultiPolygon, pt XY) bool {
+	for i := 0; i < mp.NumPolygons(); i++ {
+		poly := mp.PolygonN(i)
+		if pointRingSide(pt, poly.ExteriorRing()) != interior {
+			continue
+		}
+		inHole := false
+		for j := 0; j < poly.NumInteriorRings(); j++ {
+			if pointRingSide(pt, poly.InteriorRingN(j)) == interior {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// assembleOverlayRings chains the surviving sub-segments back together at
+// shared endpoints to reconstruct a set of closed rings.
+func assembleOverlayRings(chains []*overlayChain) [][]XY {
+	byStart := make(map[XY][]*overlayChain)
+	for _, c := range chains {
+		byStart[c.pts[0]] = append(byStart[c.pts[0]], c)
+	}
+
+	var rings [][]XY
+	for _, start := range chains {
+		if start.used {
+			continue
+		}
+		var ring []XY
+		cur := start
+		for {
+			cur.used = true
+			if len(ring) == 0 {
+				ring = append(ring, cur.pts...)
+			} else {
+				ring = append(ring, cur.pts[1:]...)
+			}
+			end := cur.pts[len(cur.pts)-1]
+			if end == ring[0] {
+				break
+			}
+			next := nextUnusedChain(byStart, end)
+			if next == nil {
+				// Dangling chain: the input wasn't a closed set of rings.
+				// This shouldn't happen for valid noded polygon boundaries.
+				break
+			}
+			cur = next
+		}
+		if len(ring) >= 4 {
+			rings = append(rings, ring)
+		}
+	}
+	return rings
+}
+
+func nextUnusedChain(byStart map[XY][]*overlayChain, at XY) *overlayChain {
+	for _, c := range byStart[at] {
+		if !c.used {
+			return c
+		}
+	}
+	return nil
+}
+
+// buildMultiPolygonFromRings groups the rings produced by assembleOverlayRings
+// into polygons, assigning each hole (a ring with negative signed area) to
+// the smallest enclosing shell (a ring with positive signed area).
+func buildMultiPolygonFromRings(rings [][]XY) MultiPolygon {
+	type shell struct {
+		ext   []XY
+		holes [][]XY
+	}
+	var shells []*shell
+	var holes [][]XY
+	for _, ring := range rings {
+		if signedRingArea(ring) > 0 {
+			shells = append(shells, &shell{ext: ring})
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+	for _, hole := range holes {
+		pt := hole[0]
+		var best *shell
+		bestArea := math.Inf(+1)
+		for _, s := range shells {
+			if !ringContainsPoint(s.ext, pt) {
+				continue
+			}
+			if area := math.Abs(signedRingArea(s.ext)); area < bestArea {
+				bestArea = area
+				best = s
+			}
+		}
+		if best != nil {
+			best.holes = append(best.holes, hole)
+		}
+	}
+
+	coords := make([][][]Coordinates, len(shells))
+	for i, s := range shells {
+		polyRings := make([][]Coordinates, 1+len(s.holes))
+		polyRings[0] = xysToCoordinates(s.ext)
+		for j, h := range s.holes {
+			polyRings[j+1] = xysToCoordinates(h)
+		}
+		coords[i] = polyRings
+	}
+	mp, err := NewMultiPolygonC(coords)
+	if err != nil {
+		panic(err)
+	}
+	return mp
+}
+
+func multiPolygonOrPolygon(mp MultiPolygon) Geometry {
+	if mp.NumPolygons() == 1 {
+		return mp.PolygonN(0)
+	}
+	return mp
+}
+
+func xysToCoordinates(xys []XY) []Coordinates {
+	cs := make([]Coordinates, len(xys))
+	for i, xy := range xys {
+		cs[i] = Coordinates{XY: xy}
+	}
+	return cs
+}
+
+// signedRingArea gives the signed area enclosed by ring using the
+// shoelace formula; the sign is positive for a counter-clockwise ring and
+// negative for a clockwise ring.
+func signedRingArea(ring []XY) float64 {
+	var area float64
+	for i := 0; i < len(ring)-1; i++ {
+		a, b := ring[i], ring[i+1]
+		area += a.X*b.Y - b.X*a.Y
+	}
+	return area / 2
+}
+
+// ringContainsPoint performs a ray-casting point-in-polygon test against a
+// single closed ring (given as a slice of XY with the first and last points
+// equal).
+func ringContainsPoint(ring []XY, pt XY) bool {
+	inside := false
+	n := len(ring) - 1
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := ring[j], ring[i]
+		if (a.Y > pt.Y) != (b.Y > pt.Y) {
+			xIntersect := (b.X-a.X)*(pt.Y-a.Y)/(b.Y-a.Y) + a.X
+			if pt.X < xIntersect {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
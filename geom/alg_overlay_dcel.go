@@ -0,0 +1,678 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// maskA and maskB are the bit flags used to distinguish the two operands'
+// geometries when building their DCELs for OverlayDCEL.
+const (
+	maskA uint8 = 1 << 0
+	maskB uint8 = 1 << 1
+)
+
+// populatedMask is the union of maskA and maskB, used to mark an edge or
+// face as having come from the actual input geometry of one of the
+// operands, as opposed to one of the invisible "ghost" edges addGhosts
+// splices in purely to connect an operand's rings back to the rest of the
+// plane.
+const populatedMask uint8 = maskA | maskB
+
+// locInterior and locBoundary mark a vertexRecord's locLabel as lying in an
+// operand's interior or on its boundary (e.g. an open line's endpoint).
+//
+// TODO: these don't yet distinguish which operand (A or B) a given bit
+// refers to -- see the mod-2 boundary rule TODO beside locLabel's use in
+// newDCELFromMultiLineString.
+const (
+	locInterior uint8 = 1 << 2
+	locBoundary uint8 = 1 << 3
+)
+
+// OverlayOptions configures the DCEL-based overlay operations (Union,
+// Intersection, Difference, SymmetricDifference and OverlayDCEL). The zero
+// value disables every option.
+type OverlayOptions struct {
+	// SnapTolerance rounds every input coordinate to the nearest multiple
+	// of SnapTolerance before noding, as a cheap approximation of GEOS'
+	// snap-rounding: it pulls together nearly-coincident vertices (the
+	// usual cause of the sliver faces and noding failures that plague
+	// floating-point overlay) at the cost of perturbing the input by up
+	// to SnapTolerance. A value of 0 disables snapping.
+	SnapTolerance float64
+
+	// Noder, if set, is called with the two operands before OverlayDCEL
+	// nodes them, and returns extra points that must be treated as
+	// interactions (in addition to the ring-crossings and vertices
+	// overlayDCELInteractions already finds on its own) -- typically the
+	// hot pixel set of a real snap-rounding pass over both operands
+	// together. This is the hook package
+	// github.com/peterstace/simplefeatures/snapround plugs into: snapround
+	// already imports geom (to work with Geometry/XY/Sequence), so geom
+	// can't import snapround back without a cycle, and Noder lets the
+	// wiring happen from the snapround side instead, e.g.:
+	//
+	//	geom.Union(a, b, geom.OverlayOptions{Noder: snapround.AsNoder(grid)})
+	Noder func(a, b Geometry) map[XY]struct{}
+}
+
+// snap rounds xy to the nearest multiple of o.SnapTolerance, or returns xy
+// unchanged if snapping is disabled.
+func (o OverlayOptions) snap(xy XY) XY {
+	if o.SnapTolerance == 0 {
+		return xy
+	}
+	return XY{
+		X: math.Round(xy.X/o.SnapTolerance) * o.SnapTolerance,
+		Y: math.Round(xy.Y/o.SnapTolerance) * o.SnapTolerance,
+	}
+}
+
+// Union returns the geometry representing the set union of a and b.
+//
+// Point/MultiPoint, LineString/MultiLineString and Polygon/MultiPolygon
+// operands are supported, in any combination of those three families;
+// GeometryCollection operands return an error, matching Relate. Where one
+// operand's result is entirely covered by the other's (e.g. a Point that
+// lies inside a Polygon), the lower-dimension operand is dropped from the
+// result rather than surfaced redundantly as a GeometryCollection.
+func Union(a, b Geometry, opts ...OverlayOptions) (Geometry, error) {
+	return overlay(a, b, func(insideA, insideB bool) bool {
+		return insideA || insideB
+	}, opts...)
+}
+
+// Intersection returns the geometry representing the set intersection of a
+// and b. See Union for the supported operand types.
+func Intersection(a, b Geometry, opts ...OverlayOptions) (Geometry, error) {
+	return overlay(a, b, func(insideA, insideB bool) bool {
+		return insideA && insideB
+	}, opts...)
+}
+
+// Difference returns the geometry representing the parts of a that do not
+// lie in b. See Union for the supported operand types.
+func Difference(a, b Geometry, opts ...OverlayOptions) (Geometry, error) {
+	return overlay(a, b, func(insideA, insideB bool) bool {
+		return insideA && !insideB
+	}, opts...)
+}
+
+// SymmetricDifference returns the geometry representing the set symmetric
+// difference of a and b. See Union for the supported operand types.
+func SymmetricDifference(a, b Geometry, opts ...OverlayOptions) (Geometry, error) {
+	return overlay(a, b, func(insideA, insideB bool) bool {
+		return insideA != insideB
+	}, opts...)
+}
+
+// overlayOpt collapses opts down to the single OverlayOptions in effect,
+// the same variadic-options-with-a-default convention used by
+// WKBReader/WKBWriter's functional options elsewhere in this package, but
+// as a single struct value (as the request asked for) rather than a list
+// of option-setting funcs.
+func overlayOpt(opts []OverlayOptions) OverlayOptions {
+	if len(opts) == 0 {
+		return OverlayOptions{}
+	}
+	return opts[0]
+}
+
+// overlay dispatches a and b to the overlay implementation for their
+// geometry family (mirroring relate's family dispatch in alg_relate.go),
+// and applies keep to decide which parts of the result to retain.
+func overlay(a, b Geometry, keep func(insideA, insideB bool) bool, opts ...OverlayOptions) (Geometry, error) {
+	opt := overlayOpt(opts)
+
+	if pts1, ok := asMultiPointFamily(a); ok {
+		if pts2, ok := asMultiPointFamily(b); ok {
+			return overlayPoints(pts1, pts2, keep, opt), nil
+		}
+		if lns2, ok := asMultiLineStringFamily(b); ok {
+			return overlayPointsAndLines(pts1, lns2, keep, opt), nil
+		}
+		if polys2, ok := asMultiPolygonFamily(b); ok {
+			return overlayPointsAndPolygons(pts1, polys2, keep, opt), nil
+		}
+	}
+	if lns1, ok := asMultiLineStringFamily(a); ok {
+		if pts2, ok := asMultiPointFamily(b); ok {
+			return overlayPointsAndLines(pts2, lns1, flip(keep), opt), nil
+		}
+		if lns2, ok := asMultiLineStringFamily(b); ok {
+			return overlayLines(lns1, lns2, keep, opt), nil
+		}
+		if polys2, ok := asMultiPolygonFamily(b); ok {
+			return overlayLinesAndPolygons(lns1, polys2, keep, opt), nil
+		}
+	}
+	if polys1, ok := asMultiPolygonFamily(a); ok {
+		if pts2, ok := asMultiPointFamily(b); ok {
+			return overlayPointsAndPolygons(pts2, polys1, flip(keep), opt), nil
+		}
+		if lns2, ok := asMultiLineStringFamily(b); ok {
+			return overlayLinesAndPolygons(lns2, polys1, flip(keep), opt), nil
+		}
+		if polys2, ok := asMultiPolygonFamily(b); ok {
+			return overlayPolygons(polys1, polys2, keep, opt)
+		}
+	}
+	return nil, fmt.Errorf(
+		"geom: overlay doesn't support %v and %v yet (only Point/MultiPoint, "+
+			"LineString/MultiLineString and Polygon/MultiPolygon operands are supported)",
+		a.Type(), b.Type(),
+	)
+}
+
+// flip adapts keep to the case where the overlay helper it's passed to
+// receives its two operands in the opposite order to the original a, b
+// passed to Union/Intersection/Difference/SymmetricDifference.
+func flip(keep func(insideA, insideB bool) bool) func(insideA, insideB bool) bool {
+	return func(insideA, insideB bool) bool {
+		return keep(insideB, insideA)
+	}
+}
+
+// overlayPoints computes the boolean set operation selected by keep between
+// two point sets.
+func overlayPoints(mp1, mp2 MultiPoint, keep func(insideA, insideB bool) bool, opt OverlayOptions) Geometry {
+	set2 := make(map[XY]bool)
+	for i := 0; i < mp2.NumPoints(); i++ {
+		if xy, ok := mp2.PointN(i).XY(); ok {
+			set2[opt.snap(xy)] = true
+		}
+	}
+	set1 := make(map[XY]bool)
+	for i := 0; i < mp1.NumPoints(); i++ {
+		if xy, ok := mp1.PointN(i).XY(); ok {
+			set1[opt.snap(xy)] = true
+		}
+	}
+
+	result := make(map[XY]bool)
+	for xy := range set1 {
+		if keep(true, set2[xy]) {
+			result[xy] = true
+		}
+	}
+	for xy := range set2 {
+		if keep(set1[xy], true) {
+			result[xy] = true
+		}
+	}
+	return pointsOrMultiPoint(result)
+}
+
+// overlayLines computes the boolean set operation selected by keep between
+// two line sets. Each input is noded against the other at every crossing
+// point found by LineSegmentIntersections (the same approach Overlay uses
+// for rings), and each resulting sub-segment is kept or dropped by testing
+// whether it lies on top of the other operand.
+func overlayLines(mls1, mls2 MultiLineString, keep func(insideA, insideB bool) bool, opt OverlayOptions) Geometry {
+	seqs1 := lineStringSeqs(mls1)
+	seqs2 := lineStringSeqs(mls2)
+
+	crossings := LineSegmentIntersections(mls1, mls2)
+	interactions := make(map[XY]struct{})
+	for _, pt := range crossings {
+		interactions[opt.snap(pt.XY())] = struct{}{}
+	}
+	for _, seq := range seqs1 {
+		addSnappedVerticesToInteractions(seq, interactions, opt)
+	}
+	for _, seq := range seqs2 {
+		addSnappedVerticesToInteractions(seq, interactions, opt)
+	}
+
+	kept := make(edgeSet)
+	var segments [][]XY
+	addIfNew := func(segment []XY) {
+		start, end := segment[0], segment[len(segment)-1]
+		mid := segment[1 : len(segment)-1]
+		if kept.containsStartIntermediateEnd(start, mid, end) || kept.containsStartIntermediateEnd(end, reverseXYs(mid), start) {
+			return
+		}
+		kept.insertStartIntermediateEnd(start, mid, end)
+		segments = append(segments, segment)
+	}
+
+	for _, seq := range seqs1 {
+		forEachNonInteractingSegment(seq, interactions, opt, func(segment []XY) {
+			if keep(true, multiLineStringContainsPoint(mls2, segmentMidpoint(segment))) {
+				addIfNew(segment)
+			}
+		})
+	}
+	for _, seq := range seqs2 {
+		forEachNonInteractingSegment(seq, interactions, opt, func(segment []XY) {
+			if keep(multiLineStringContainsPoint(mls1, segmentMidpoint(segment)), true) {
+				addIfNew(segment)
+			}
+		})
+	}
+
+	return linesOrMultiLineString(segments)
+}
+
+// overlayPointsAndLines computes the boolean set operation selected by keep
+// between a point set and a line set.
+//
+// A point and a line can only interact where the point lies on the line, so
+// unlike overlayLines there's no noding to do: every input point is kept or
+// dropped as a whole by testing it against the other operand's point set,
+// and the line operand -- having no lower-dimensional pieces to subtract --
+// is kept or dropped as a whole too.
+func overlayPointsAndLines(mp MultiPoint, mls MultiLineString, keep func(insideA, insideB bool) bool, opt OverlayOptions) Geometry {
+	var pts []Geometry
+	for i := 0; i < mp.NumPoints(); i++ {
+		xy, ok := mp.PointN(i).XY()
+		if !ok {
+			continue
+		}
+		xy = opt.snap(xy)
+		onLine := multiLineStringContainsPoint(mls, xy)
+		if keep(true, onLine) && !(onLine && keep(false, true)) {
+			// A point that lies on the line is already represented by
+			// the line itself when the line is kept; only surface the
+			// points that aren't.
+			pts = append(pts, xyAsPoint(xy))
+		}
+	}
+
+	var parts []Geometry
+	if keep(false, true) {
+		parts = append(parts, linesOrMultiLineString(lineStringSeqsAsSegments(lineStringSeqs(mls))))
+	}
+	parts = append(parts, pts...)
+	return collapseOverlayParts(parts)
+}
+
+// overlayPointsAndPolygons computes the boolean set operation selected by
+// keep between a point set and a polygon set, using the same whole-point,
+// whole-polygon approach as overlayPointsAndLines.
+func overlayPointsAndPolygons(mp MultiPoint, mpoly MultiPolygon, keep func(insideA, insideB bool) bool, opt OverlayOptions) Geometry {
+	var pts []Geometry
+	for i := 0; i < mp.NumPoints(); i++ {
+		xy, ok := mp.PointN(i).XY()
+		if !ok {
+			continue
+		}
+		xy = opt.snap(xy)
+		inPoly := multiPolygonContainsPoint(mpoly, xy) || multiPolygonBoundaryContainsPoint(mpoly, xy)
+		if keep(true, inPoly) && !(inPoly && keep(false, true)) {
+			pts = append(pts, xyAsPoint(xy))
+		}
+	}
+
+	var parts []Geometry
+	if keep(false, true) {
+		parts = append(parts, multiPolygonOrPolygon(mpoly))
+	}
+	parts = append(parts, pts...)
+	return collapseOverlayParts(parts)
+}
+
+// overlayLinesAndPolygons computes the boolean set operation selected by
+// keep between a line set and a polygon set.
+//
+// As with overlayPointsAndLines, the polygon operand has no
+// lower-dimensional part to subtract, so it's kept or dropped as a whole;
+// the line operand is noded against the polygon's rings (reusing the same
+// interaction machinery as overlayPolygons) so that each of its
+// sub-segments can be classified as inside or outside the polygon.
+func overlayLinesAndPolygons(mls MultiLineString, mpoly MultiPolygon, keep func(insideA, insideB bool) bool, opt OverlayOptions) Geometry {
+	ringSeqs := multiPolygonRingSeqs(mpoly)
+	crossings := LineSegmentIntersections(mls, ringSeqsToMultiLineString(ringSeqs))
+	interactions := make(map[XY]struct{})
+	for _, pt := range crossings {
+		interactions[opt.snap(pt.XY())] = struct{}{}
+	}
+	for _, seq := range ringSeqs {
+		addSnappedVerticesToInteractions(seq, interactions, opt)
+	}
+	for _, seq := range lineStringSeqs(mls) {
+		addSnappedVerticesToInteractions(seq, interactions, opt)
+	}
+
+	var segments [][]XY
+	for _, seq := range lineStringSeqs(mls) {
+		forEachNonInteractingSegment(seq, interactions, opt, func(segment []XY) {
+			mid := segmentMidpoint(segment)
+			inPoly := multiPolygonContainsPoint(mpoly, mid) || multiPolygonBoundaryContainsPoint(mpoly, mid)
+			if keep(true, inPoly) && !(inPoly && keep(false, true)) {
+				segments = append(segments, segment)
+			}
+		})
+	}
+
+	var parts []Geometry
+	if keep(false, true) {
+		parts = append(parts, multiPolygonOrPolygon(mpoly))
+	}
+	if len(segments) > 0 {
+		parts = append(parts, linesOrMultiLineString(segments))
+	}
+	return collapseOverlayParts(parts)
+}
+
+// overlayPolygons computes the boolean set operation selected by keep
+// between two polygon sets, via the DCEL-based planar subdivision built by
+// OverlayDCEL.
+func overlayPolygons(mpoly1, mpoly2 MultiPolygon, keep func(insideA, insideB bool) bool, opt OverlayOptions) (Geometry, error) {
+	dcel, err := OverlayDCEL(mpoly1, mpoly2, opt)
+	if err != nil {
+		return nil, err
+	}
+	return dcel.extractFaces(keep), nil
+}
+
+// collapseOverlayParts assembles the non-empty geometries in parts into a
+// single result: empty if there are none, the geometry itself if there's
+// exactly one (so e.g. Union of a Point wholly inside a Polygon returns
+// just the Polygon rather than a redundant single-element
+// GeometryCollection), and a GeometryCollection otherwise.
+func collapseOverlayParts(parts []Geometry) Geometry {
+	var nonEmpty []Geometry
+	for _, g := range parts {
+		if !g.IsEmpty() {
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	switch len(nonEmpty) {
+	case 0:
+		return NewGeometryCollection(nil)
+	case 1:
+		return nonEmpty[0]
+	default:
+		return NewGeometryCollection(nonEmpty)
+	}
+}
+
+// pointsOrMultiPoint builds a Point or MultiPoint (collapsing to a bare
+// Point when there's only one, the same convention multiPolygonOrPolygon
+// uses for polygons) from the XY values in xys.
+func pointsOrMultiPoint(xys map[XY]bool) Geometry {
+	if len(xys) == 0 {
+		return NewGeometryCollection(nil)
+	}
+	var coords []Coordinates
+	for xy := range xys {
+		coords = append(coords, Coordinates{XY: xy})
+	}
+	mp := NewMultiPointC(coords)
+	if mp.NumPoints() == 1 {
+		return mp.PointN(0)
+	}
+	return mp
+}
+
+// linesOrMultiLineString builds a LineString or MultiLineString (collapsing
+// to a bare LineString when there's only one) from segments, each a slice
+// of XY making up one line string's coordinates.
+func linesOrMultiLineString(segments [][]XY) Geometry {
+	if len(segments) == 0 {
+		return NewGeometryCollection(nil)
+	}
+	coords := make([][]Coordinates, len(segments))
+	for i, seg := range segments {
+		coords[i] = xysToCoordinates(seg)
+	}
+	mls, err := NewMultiLineStringC(coords)
+	if err != nil {
+		panic(err)
+	}
+	if mls.NumLineStrings() == 1 {
+		return mls.LineStringN(0)
+	}
+	return mls
+}
+
+// lineStringSeqs gives the coordinate Sequence of each line string in mls.
+func lineStringSeqs(mls MultiLineString) []Sequence {
+	seqs := make([]Sequence, mls.NumLineStrings())
+	for i := range seqs {
+		seqs[i] = mls.LineStringN(i).Coordinates()
+	}
+	return seqs
+}
+
+// lineStringSeqsAsSegments converts seqs back into the []XY segment form
+// linesOrMultiLineString expects, with no splitting -- used when an
+// operand's line set is kept in full rather than noded and classified.
+func lineStringSeqsAsSegments(seqs []Sequence) [][]XY {
+	segments := make([][]XY, len(seqs))
+	for i, seq := range seqs {
+		segment := make([]XY, seq.Length())
+		for j := range segment {
+			segment[j] = seq.GetXY(j)
+		}
+		segments[i] = segment
+	}
+	return segments
+}
+
+// segmentMidpoint gives the midpoint between the first and last XY in
+// segment, used as the representative point classifySegments-style overlay
+// helpers test against the other operand.
+func segmentMidpoint(segment []XY) XY {
+	a, b := segment[0], segment[len(segment)-1]
+	return XY{(a.X + b.X) / 2, (a.Y + b.Y) / 2}
+}
+
+// multiLineStringContainsPoint reports whether pt lies on one of mls's
+// segments.
+func multiLineStringContainsPoint(mls MultiLineString, pt XY) bool {
+	for _, seq := range lineStringSeqs(mls) {
+		n := seq.Length()
+		for i := 0; i < n-1; i++ {
+			if onSegment(seq.GetXY(i), seq.GetXY(i+1), pt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// multiPolygonBoundaryContainsPoint reports whether pt lies on the boundary
+// of one of mpoly's rings (as opposed to strictly inside it, which
+// multiPolygonContainsPoint tests).
+func multiPolygonBoundaryContainsPoint(mpoly MultiPolygon, pt XY) bool {
+	for _, seq := range multiPolygonRingSeqs(mpoly) {
+		n := seq.Length()
+		for i := 0; i < n-1; i++ {
+			if onSegment(seq.GetXY(i), seq.GetXY(i+1), pt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addSnappedVerticesToInteractions is addRingVerticesToInteractions (see
+// alg_overlay.go), but snapping each vertex through opt first so that the
+// interaction set it builds lines up with the snapped vertices the DCEL
+// builders in dcel.go key their own interaction-set lookups against.
+func addSnappedVerticesToInteractions(seq Sequence, interactions map[XY]struct{}, opt OverlayOptions) {
+	for i := 0; i < seq.Length(); i++ {
+		interactions[opt.snap(seq.GetXY(i))] = struct{}{}
+	}
+}
+
+// OverlayDCEL nodes a and b against each other and merges their planar
+// subdivisions into a single DCEL whose faces are labelled with which of a
+// and b (or both) they fall within. It's the building block behind
+// overlayPolygons; calling it directly lets a caller extract more than one
+// boolean result (via ExtractFaces) from a single noding and merge pass.
+//
+// Only Polygon and MultiPolygon operands are supported; use Union,
+// Intersection, Difference or SymmetricDifference for the other geometry
+// families.
+func OverlayDCEL(a, b Geometry, opts ...OverlayOptions) (*DCEL, error) {
+	opt := overlayOpt(opts)
+
+	mp1, ok1 := asMultiPolygonFamily(a)
+	mp2, ok2 := asMultiPolygonFamily(b)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("geom: OverlayDCEL only supports Polygon/MultiPolygon operands, got %v and %v", a.Type(), b.Type())
+	}
+
+	interactions := overlayDCELInteractions(mp1, mp2, opt)
+
+	ghosts, err := NewMultiLineStringC(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	dcelA := newDCELFromGeometry(mp1, ghosts, maskA, interactions, opt)
+	dcelB := newDCELFromGeometry(mp2, ghosts, maskB, interactions, opt)
+
+	merged := mergeDCELs(dcelA, dcelB)
+	merged.assignFaces()
+	return merged, nil
+}
+
+// overlayDCELInteractions computes the set of points at which mp1 and mp2's
+// ring boundaries need to be noded: every crossing point between the two
+// operands (found via the Bentley-Ottmann sweep in
+// LineSegmentIntersections), plus every existing ring vertex of both
+// operands, all snapped through opt, plus (if opt.Noder is set) whatever
+// extra interaction points a real noding pass over both operands finds.
+func overlayDCELInteractions(mp1, mp2 MultiPolygon, opt OverlayOptions) map[XY]struct{} {
+	seqs1 := multiPolygonRingSeqs(mp1)
+	seqs2 := multiPolygonRingSeqs(mp2)
+
+	crossings := LineSegmentIntersections(ringSeqsToMultiLineString(seqs1), ringSeqsToMultiLineString(seqs2))
+	interactions := make(map[XY]struct{})
+	for _, pt := range crossings {
+		interactions[opt.snap(pt.XY())] = struct{}{}
+	}
+	for _, seq := range seqs1 {
+		addSnappedVerticesToInteractions(seq, interactions, opt)
+	}
+	for _, seq := range seqs2 {
+		addSnappedVerticesToInteractions(seq, interactions, opt)
+	}
+	if opt.Noder != nil {
+		for pt := range opt.Noder(multiPolygonOrPolygon(mp1), multiPolygonOrPolygon(mp2)) {
+			interactions[opt.snap(pt)] = struct{}{}
+		}
+	}
+	return interactions
+}
+
+// mergeDCELs combines two independently-built DCELs that have already been
+// noded against a shared interactions set (so that coincident vertices use
+// identical XY keys) into a single DCEL describing their overlay.
+func mergeDCELs(a, b *DCEL) *DCEL {
+	merged := &DCEL{vertices: make(map[XY]*vertexRecord)}
+	merged.halfEdges = append(append([]*halfEdgeRecord(nil), a.halfEdges...), b.halfEdges...)
+
+	for xy, va := range a.vertices {
+		merged.vertices[xy] = va
+	}
+	for xy, vb := range b.vertices {
+		if va, ok := merged.vertices[xy]; ok {
+			va.incidents = append(va.incidents, vb.incidents...)
+			va.label |= vb.label
+			va.locLabel |= vb.locLabel
+		} else {
+			merged.vertices[xy] = vb
+		}
+	}
+
+	for _, v := range merged.vertices {
+		relinkVertex(v)
+	}
+	return merged
+}
+
+// relinkVertex re-sorts the half-edges incident to (outgoing from) v by
+// angle, and rewires each edge's twin.next/next.prev pointers to its
+// angularly-adjacent neighbour, so that face-walking with forEachEdge
+// correctly crosses between v's two original DCELs wherever they share v as
+// a vertex.
+func relinkVertex(v *vertexRecord) {
+	if len(v.incidents) < 2 {
+		return
+	}
+	sort.Slice(v.incidents, func(i, j int) bool {
+		return edgeAngle(v.incidents[i]) < edgeAngle(v.incidents[j])
+	})
+	n := len(v.incidents)
+	for i, e := range v.incidents {
+		prevEdge := v.incidents[(i-1+n)%n]
+		e.twin.next = prevEdge
+		prevEdge.prev = e.twin
+	}
+}
+
+// edgeAngle gives the angle (as used by atan2) of the direction e leaves its
+// origin vertex in.
+func edgeAngle(e *halfEdgeRecord) float64 {
+	d := e.secondXY().Sub(e.origin.coords)
+	return math.Atan2(d.Y, d.X)
+}
+
+// assignFaces walks every cycle of half-edges in d (following next
+// pointers), labelling each with a newly-created faceRecord whose label is
+// the union of the faceLabel bits of every edge on its boundary.
+func (d *DCEL) assignFaces() {
+	visited := make(map[*halfEdgeRecord]bool)
+	for _, e := range d.halfEdges {
+		if visited[e] {
+			continue
+		}
+		var label uint8
+		forEachEdge(e, func(edge *halfEdgeRecord) {
+			visited[edge] = true
+			label |= edge.faceLabel
+		})
+		face := &faceRecord{cycle: e, label: label}
+		forEachEdge(e, func(edge *halfEdgeRecord) {
+			edge.incident = face
+		})
+		d.faces = append(d.faces, face)
+	}
+}
+
+// extractFaces builds the Polygon/MultiPolygon made up of every face of d
+// for which keep(insideA, insideB) is true, where insideA/insideB record
+// whether that face lies within operand a/b (as built by OverlayDCEL).
+func (d *DCEL) extractFaces(keep func(insideA, insideB bool) bool) Geometry {
+	var rings [][]XY
+	for _, f := range d.faces {
+		insideA := f.label&maskA != 0
+		insideB := f.label&maskB != 0
+		if !keep(insideA, insideB) {
+			continue
+		}
+		if ring := faceRing(f); len(ring) >= 4 {
+			rings = append(rings, ring)
+		}
+	}
+	if len(rings) == 0 {
+		return NewGeometryCollection(nil)
+	}
+	return multiPolygonOrPolygon(buildMultiPolygonFromRings(rings))
+}
+
+// ExtractFaces is the exported form of extractFaces, for callers of
+// OverlayDCEL: e.g. a keep of insideA && insideB gives the intersection.
+func (d *DCEL) ExtractFaces(keep func(insideA, insideB bool) bool) Geometry {
+	return d.extractFaces(keep)
+}
+
+// faceRing collects the boundary of f's cycle into a closed ring.
+func faceRing(f *faceRecord) []XY {
+	var ring []XY
+	forEachEdge(f.cycle, func(e *halfEdgeRecord) {
+		ring = append(ring, e.origin.coords)
+		ring = append(ring, e.intermediate...)
+	})
+	if len(ring) > 0 {
+		ring = append(ring, ring[0])
+	}
+	return ring
+}
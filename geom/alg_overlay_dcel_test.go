@@ -0,0 +1,71 @@
+package geom_test
+
+import (
+	"testing"
+
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+func TestOverlayDCEL(t *testing.T) {
+	// Two overlapping unit squares, offset by half a unit in both axes.
+	const (
+		squareA = "POLYGON((0 0,0 1,1 1,1 0,0 0))"
+		squareB = "POLYGON((0.5 0.5,0.5 1.5,1.5 1.5,1.5 0.5,0.5 0.5))"
+	)
+	for _, tt := range []struct {
+		name string
+		op   func(a, b geom.Geometry) (geom.Geometry, error)
+		want string
+	}{
+		{
+			name: "Union",
+			op:   func(a, b geom.Geometry) (geom.Geometry, error) { return geom.Union(a, b) },
+			want: "POLYGON((0 0,0 1,0.5 1,0.5 1.5,1.5 1.5,1.5 0.5,1 0.5,1 0,0 0))",
+		},
+		{
+			name: "Intersection",
+			op:   func(a, b geom.Geometry) (geom.Geometry, error) { return geom.Intersection(a, b) },
+			want: "POLYGON((0.5 0.5,0.5 1,1 1,1 0.5,0.5 0.5))",
+		},
+		{
+			name: "Difference",
+			op:   func(a, b geom.Geometry) (geom.Geometry, error) { return geom.Difference(a, b) },
+			want: "POLYGON((0 0,0 1,0.5 1,0.5 0.5,1 0.5,1 0,0 0))",
+		},
+		{
+			name: "SymmetricDifference",
+			op:   func(a, b geom.Geometry) (geom.Geometry, error) { return geom.SymmetricDifference(a, b) },
+			want: "MULTIPOLYGON(((0 0,0 1,0.5 1,0.5 0.5,1 0.5,1 0,0 0)),((0.5 1,0.5 1.5,1.5 1.5,1.5 0.5,1 0.5,1 1,0.5 1)))",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a := geomFromWKT(t, squareA)
+			b := geomFromWKT(t, squareB)
+			got, err := tt.op(a, b)
+			expectNoErr(t, err)
+			want := geomFromWKT(t, tt.want)
+			expectGeomEq(t, got, want, geom.IgnoreOrder)
+		})
+	}
+}
+
+func TestOverlayDCELDisjointInputs(t *testing.T) {
+	a := geomFromWKT(t, "POLYGON((0 0,0 1,1 1,1 0,0 0))")
+	b := geomFromWKT(t, "POLYGON((10 10,10 11,11 11,11 10,10 10))")
+
+	union, err := geom.Union(a, b)
+	expectNoErr(t, err)
+	if n := union.AsMultiPolygon().NumPolygons(); n != 2 {
+		t.Errorf("Union of disjoint polygons: got %d polygons, want 2", n)
+	}
+
+	intersection, err := geom.Intersection(a, b)
+	expectNoErr(t, err)
+	if !intersection.IsEmpty() {
+		t.Errorf("Intersection of disjoint polygons: got %v, want empty", intersection.AsText())
+	}
+
+	diff, err := geom.Difference(a, b)
+	expectNoErr(t, err)
+	expectGeomEq(t, diff, a, geom.IgnoreOrder)
+}
@@ -0,0 +1,36 @@
+package geom_test
+
+import (
+	"testing"
+
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+// TestPolygonOverlayMethodsDelegateToDCEL checks that Polygon.Union,
+// Polygon.Difference and Polygon.SymmetricDifference agree with the
+// package-level Union/Difference/SymmetricDifference on the same inputs,
+// since the former are thin wrappers around the latter rather than a second,
+// independently-maintained overlay engine.
+func TestPolygonOverlayMethodsDelegateToDCEL(t *testing.T) {
+	a := geomFromWKT(t, "POLYGON((0 0,0 1,1 1,1 0,0 0))").AsPolygon()
+	b := geomFromWKT(t, "POLYGON((0.5 0.5,0.5 1.5,1.5 1.5,1.5 0.5,0.5 0.5))").AsPolygon()
+
+	t.Run("Union", func(t *testing.T) {
+		got := a.Union(b)
+		want, err := geom.Union(a, b)
+		expectNoErr(t, err)
+		expectGeomEq(t, got, want, geom.IgnoreOrder)
+	})
+	t.Run("Difference", func(t *testing.T) {
+		got := a.Difference(b)
+		want, err := geom.Difference(a, b)
+		expectNoErr(t, err)
+		expectGeomEq(t, got, want, geom.IgnoreOrder)
+	})
+	t.Run("SymmetricDifference", func(t *testing.T) {
+		got := a.SymmetricDifference(b)
+		want, err := geom.SymmetricDifference(a, b)
+		expectNoErr(t, err)
+		expectGeomEq(t, got, want, geom.IgnoreOrder)
+	})
+}
@@ -0,0 +1,82 @@
+package geom
+
+import (
+	"sync"
+
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+// rtreeIndex lazily builds and caches an *rtree.RTree. Geometry types hang
+// one of these off a pointer field so that it's built at most once no
+// matter how many value copies of the geometry request it.
+type rtreeIndex struct {
+	once sync.Once
+	tree *rtree.RTree
+}
+
+// index lazily bulk-loads an R-tree over a degenerate box per point (so
+// that Nearest, NearestK, and PointsInBBox can be served by R-tree queries
+// rather than scanning every point in m).
+func (m MultiPoint) index() *rtree.RTree {
+	m.spatialIndex.once.Do(func() {
+		items := make([]rtree.BulkItem, m.NumPoints())
+		for i := range items {
+			xy := m.PointN(i).XY()
+			items[i] = rtree.BulkItem{
+				Box:      rtree.Box{MinX: xy.X, MinY: xy.Y, MaxX: xy.X, MaxY: xy.Y},
+				RecordID: i,
+			}
+		}
+		m.spatialIndex.tree = rtree.BulkLoad(items)
+	})
+	return m.spatialIndex.tree
+}
+
+// Nearest returns the point in m closest to pt, along with true. If m is
+// empty, it returns the zero Point and false.
+func (m MultiPoint) Nearest(pt Point) (Point, bool) {
+	if m.IsEmpty() {
+		return Point{}, false
+	}
+	xy := pt.XY()
+	target := rtree.Box{MinX: xy.X, MinY: xy.Y, MaxX: xy.X, MaxY: xy.Y}
+
+	var nearest int
+	m.index().KNN(target, func(recordID int, dist float64) bool {
+		nearest = recordID
+		return false
+	})
+	return m.PointN(nearest), true
+}
+
+// NearestK returns up to k of the points in m closest to pt, in increasing
+// order of distance from pt. If m has fewer than k points, every point in m
+// is returned.
+func (m MultiPoint) NearestK(pt Point, k int) []Point {
+	if k <= 0 {
+		return nil
+	}
+	xy := pt.XY()
+	target := rtree.Box{MinX: xy.X, MinY: xy.Y, MaxX: xy.X, MaxY: xy.Y}
+
+	results := make([]Point, 0, k)
+	m.index().KNN(target, func(recordID int, dist float64) bool {
+		results = append(results, m.PointN(recordID))
+		return len(results) < k
+	})
+	return results
+}
+
+// PointsInBBox returns every point in m whose coordinates fall within env.
+func (m MultiPoint) PointsInBBox(env Envelope) []Point {
+	var results []Point
+	m.index().PrioritySearch(env.box(), func(recordID int) error {
+		xy := m.PointN(recordID).XY()
+		if NewEnvelope(xy).Distance(env) > 0 {
+			return rtree.Stop
+		}
+		results = append(results, m.PointN(recordID))
+		return nil
+	})
+	return results
+}
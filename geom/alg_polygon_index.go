@@ -0,0 +1,110 @@
+package geom
+
+import (
+	"math"
+	"sort"
+
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+// index bulk-loads an R-tree over the envelopes of mp's child Polygons, so
+// that Nearest, NearestK, and PolygonsInBBox only need to consider
+// candidates whose envelope is a plausible match, rather than scanning
+// every Polygon. As with MultiLineString.index, it's rebuilt on every call
+// rather than cached, since a Polygon's envelope is only a bound on its
+// true shape and candidates still need verifying against it.
+func (mp MultiPolygon) index() *rtree.RTree {
+	var items []rtree.BulkItem
+	for i := 0; i < mp.NumPolygons(); i++ {
+		env, ok := mp.PolygonN(i).Envelope()
+		if !ok {
+			continue
+		}
+		items = append(items, rtree.BulkItem{Box: env.box(), RecordID: i})
+	}
+	return rtree.BulkLoad(items)
+}
+
+// Nearest returns the Polygon in mp closest to pt, along with true. If mp
+// has no non-empty Polygons, it returns the zero Polygon and false.
+//
+// Candidates are visited in order of increasing envelope distance from pt;
+// the search stops as soon as a candidate's envelope is already further
+// away than the best true distance found so far, since every later
+// candidate can only be at least as far.
+func (mp MultiPolygon) Nearest(pt Point) (Polygon, bool) {
+	ptEnv := NewEnvelope(pt.XY())
+	best := math.Inf(+1)
+	bestIdx := -1
+
+	mp.index().PrioritySearch(ptEnv.box(), func(recordID int) error {
+		childEnv, _ := mp.PolygonN(recordID).Envelope()
+		if childEnv.Distance(ptEnv) > best {
+			return rtree.Stop
+		}
+		if d, ok := distance(pt, mp.PolygonN(recordID)); ok && d < best {
+			best = d
+			bestIdx = recordID
+		}
+		return nil
+	})
+
+	if bestIdx < 0 {
+		return Polygon{}, false
+	}
+	return mp.PolygonN(bestIdx), true
+}
+
+// NearestK returns up to k of the Polygons in mp closest to pt, in
+// increasing order of distance from pt.
+func (mp MultiPolygon) NearestK(pt Point, k int) []Polygon {
+	if k <= 0 {
+		return nil
+	}
+
+	type candidate struct {
+		idx  int
+		dist float64
+	}
+	var best []candidate // kept sorted by dist, capped at length k
+
+	ptEnv := NewEnvelope(pt.XY())
+	mp.index().PrioritySearch(ptEnv.box(), func(recordID int) error {
+		childEnv, _ := mp.PolygonN(recordID).Envelope()
+		if len(best) == k && childEnv.Distance(ptEnv) > best[len(best)-1].dist {
+			return rtree.Stop
+		}
+		d, ok := distance(pt, mp.PolygonN(recordID))
+		if !ok {
+			return nil
+		}
+		i := sort.Search(len(best), func(i int) bool { return best[i].dist >= d })
+		best = append(best, candidate{})
+		copy(best[i+1:], best[i:])
+		best[i] = candidate{idx: recordID, dist: d}
+		if len(best) > k {
+			best = best[:k]
+		}
+		return nil
+	})
+
+	results := make([]Polygon, len(best))
+	for i, c := range best {
+		results[i] = mp.PolygonN(c.idx)
+	}
+	return results
+}
+
+// PolygonsInBBox returns every Polygon in mp whose envelope intersects env.
+func (mp MultiPolygon) PolygonsInBBox(env Envelope) []Polygon {
+	var results []Polygon
+	mp.index().PrioritySearch(env.box(), func(recordID int) error {
+		childEnv, _ := mp.PolygonN(recordID).Envelope()
+		if childEnv.Distance(env) > 0 {
+			return rtree.Stop
+		}
+		results = append(results, mp.PolygonN(recordID))
+		return nil
+	})
+	return results
+}
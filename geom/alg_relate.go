@@ -0,0 +1,741 @@
+package geom
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Row/column indices into an intersectionMatrix's 3x3 grid, following the
+// standard DE-9IM ordering: Interior, Boundary, Exterior.
+const (
+	imInterior = 0
+	imBoundary = 1
+	imExterior = 2
+)
+
+// intersectionMatrix is a Dimensionally Extended Nine-Intersection Model
+// matrix: im[row*3+col] holds the dimension of the intersection between
+// (Interior/Boundary/Exterior of the first operand) and
+// (Interior/Boundary/Exterior of the second operand), as one of 'F'
+// (empty), '0' (point), '1' (line) or '2' (area).
+type intersectionMatrix [9]byte
+
+func newIntersectionMatrix() intersectionMatrix {
+	var im intersectionMatrix
+	for i := range im {
+		im[i] = 'F'
+	}
+	return im
+}
+
+func (im intersectionMatrix) get(row, col int) byte {
+	return im[row*3+col]
+}
+
+// raise sets im[row][col] to d, unless it already holds a dimension at
+// least as high. Different parts of the two operands can contribute to the
+// same cell (e.g. one vertex landing on a boundary, another landing in the
+// interior), so a cell only ever increases as more of the geometry is
+// examined.
+func (im *intersectionMatrix) raise(row, col int, d byte) {
+	i := row*3 + col
+	if im[i] == 'F' || d > im[i] {
+		im[i] = d
+	}
+}
+
+func (im intersectionMatrix) String() string {
+	return string(im[:])
+}
+
+// matches reports whether im is consistent with pattern, a 9-character
+// DE-9IM pattern using 'F' (empty), '0'/'1'/'2' (exact dimension), 'T'
+// (non-empty, any dimension) and '*' (don't care).
+func (im intersectionMatrix) matches(pattern string) bool {
+	for i := 0; i < 9; i++ {
+		switch pattern[i] {
+		case '*':
+		case 'T':
+			if im[i] == 'F' {
+				return false
+			}
+		default:
+			if im[i] != pattern[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (im intersectionMatrix) transpose() intersectionMatrix {
+	var t intersectionMatrix
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			t[c*3+r] = im[r*3+c]
+		}
+	}
+	return t
+}
+
+// Relate computes the Dimensionally Extended Nine-Intersection Model
+// (DE-9IM) between g1 and g2, returning it as the standard 9-character
+// intersection matrix string (row-major: Interior/Boundary/Exterior of g1
+// against Interior/Boundary/Exterior of g2).
+//
+// Relate currently supports Point/MultiPoint, LineString/MultiLineString
+// and Polygon/MultiPolygon operands, in any combination of those three
+// families. GeometryCollection operands aren't handled and return an error;
+// every other combination returns a matrix without error, including where
+// parts of it are only approximated (see linestringFullyCoveredBy and
+// relateLineWithPolygon). Use Intersects (which delegates to the existing
+// general-purpose hasIntersection) if all that's needed is a yes/no
+// intersection test across the full set of geometry types.
+func Relate(g1, g2 Geometry) (string, error) {
+	im, err := relate(g1, g2)
+	if err != nil {
+		return "", err
+	}
+	return im.String(), nil
+}
+
+func relate(g1, g2 Geometry) (intersectionMatrix, error) {
+	if pts1, ok := asMultiPointFamily(g1); ok {
+		if pts2, ok := asMultiPointFamily(g2); ok {
+			return relatePointPoint(pts1, pts2), nil
+		}
+		if lns2, ok := asMultiLineStringFamily(g2); ok {
+			return relatePointWithLine(pts1, lns2), nil
+		}
+		if polys2, ok := asMultiPolygonFamily(g2); ok {
+			return relatePointWithPolygon(pts1, polys2), nil
+		}
+	}
+	if lns1, ok := asMultiLineStringFamily(g1); ok {
+		if pts2, ok := asMultiPointFamily(g2); ok {
+			return relatePointWithLine(pts2, lns1).transpose(), nil
+		}
+		if lns2, ok := asMultiLineStringFamily(g2); ok {
+			return relateLines(lns1, lns2), nil
+		}
+		if polys2, ok := asMultiPolygonFamily(g2); ok {
+			return relateLineWithPolygon(lns1, polys2), nil
+		}
+	}
+	if polys1, ok := asMultiPolygonFamily(g1); ok {
+		if pts2, ok := asMultiPointFamily(g2); ok {
+			return relatePointWithPolygon(pts2, polys1).transpose(), nil
+		}
+		if lns2, ok := asMultiLineStringFamily(g2); ok {
+			return relateLineWithPolygon(lns2, polys1).transpose(), nil
+		}
+		if polys2, ok := asMultiPolygonFamily(g2); ok {
+			return relatePolygons(polys1, polys2), nil
+		}
+	}
+	return intersectionMatrix{}, fmt.Errorf(
+		"geom: Relate doesn't support %v and %v yet (only Point/MultiPoint, "+
+			"LineString/MultiLineString and Polygon/MultiPolygon operands are supported)",
+		g1.Type(), g2.Type(),
+	)
+}
+
+func asMultiPointFamily(g Geometry) (MultiPoint, bool) {
+	switch g.Type() {
+	case TypePoint:
+		return NewMultiPointFromPoints([]Point{g.AsPoint()}), true
+	case TypeMultiPoint:
+		return g.AsMultiPoint(), true
+	}
+	return MultiPoint{}, false
+}
+
+func asMultiLineStringFamily(g Geometry) (MultiLineString, bool) {
+	switch g.Type() {
+	case TypeLineString:
+		return g.AsLineString().AsMultiLineString(), true
+	case TypeMultiLineString:
+		return g.AsMultiLineString(), true
+	}
+	return MultiLineString{}, false
+}
+
+func asMultiPolygonFamily(g Geometry) (MultiPolygon, bool) {
+	switch g.Type() {
+	case TypePolygon:
+		return g.AsPolygon().AsMultiPolygon(), true
+	case TypeMultiPolygon:
+		return g.AsMultiPolygon(), true
+	}
+	return MultiPolygon{}, false
+}
+
+func xyAsPoint(xy XY) Point {
+	return NewPointC(Coordinates{XY: xy})
+}
+
+// boundaryXYSet gives the set of coordinates making up g's boundary (which
+// for the Point/MultiPoint and LineString/MultiLineString families Relate
+// deals with is always itself a point set, possibly empty).
+func boundaryXYSet(g Geometry) map[XY]bool {
+	set := make(map[XY]bool)
+	b := g.Boundary()
+	if b.IsEmpty() {
+		return set
+	}
+	mp, ok := asMultiPointFamily(b)
+	if !ok {
+		return set
+	}
+	n := mp.NumPoints()
+	for i := 0; i < n; i++ {
+		set[mp.PointN(i).XY()] = true
+	}
+	return set
+}
+
+func multiPointXYSet(mp MultiPoint) map[XY]bool {
+	set := make(map[XY]bool, mp.NumPoints())
+	n := mp.NumPoints()
+	for i := 0; i < n; i++ {
+		set[mp.PointN(i).XY()] = true
+	}
+	return set
+}
+
+// relatePointPoint computes the DE-9IM matrix between two point sets.
+// Points have no boundary, so only the Interior and Exterior rows/columns
+// are ever non-empty.
+func relatePointPoint(mp1, mp2 MultiPoint) intersectionMatrix {
+	im := newIntersectionMatrix()
+	if mp1.IsEmpty() || mp2.IsEmpty() {
+		return im
+	}
+
+	set1 := multiPointXYSet(mp1)
+	set2 := multiPointXYSet(mp2)
+
+	for xy := range set1 {
+		if set2[xy] {
+			im.raise(imInterior, imInterior, '0')
+		} else {
+			im.raise(imInterior, imExterior, '0')
+		}
+	}
+	for xy := range set2 {
+		if !set1[xy] {
+			im.raise(imExterior, imInterior, '0')
+		}
+	}
+	im.raise(imExterior, imExterior, '2')
+	return im
+}
+
+// relatePointWithLine computes the DE-9IM matrix of a point set against a
+// line set. Only the point set's Interior/Exterior rows can be non-empty
+// (points have no boundary), and each point of the point set is classified
+// against the line's Interior/Boundary/Exterior.
+func relatePointWithLine(mp MultiPoint, mls MultiLineString) intersectionMatrix {
+	im := newIntersectionMatrix()
+	if mp.IsEmpty() || mls.IsEmpty() {
+		return im
+	}
+
+	boundary := boundaryXYSet(mls)
+	n := mp.NumPoints()
+	for i := 0; i < n; i++ {
+		pt := mp.PointN(i)
+		switch {
+		case boundary[pt.XY()]:
+			im.raise(imInterior, imBoundary, '0')
+		case hasIntersectionPointWithMultiLineString(pt, mls):
+			im.raise(imInterior, imInterior, '0')
+		default:
+			im.raise(imInterior, imExterior, '0')
+		}
+	}
+
+	pts := multiPointXYSet(mp)
+	im.raise(imExterior, imInterior, '1')
+	for xy := range boundary {
+		if !pts[xy] {
+			im.raise(imExterior, imBoundary, '0')
+			break
+		}
+	}
+	im.raise(imExterior, imExterior, '2')
+	return im
+}
+
+// relatePointWithPolygon computes the DE-9IM matrix of a point set against
+// a polygon set, the same way relatePointWithLine does against a line set,
+// classifying each point against the polygon's Interior/Boundary/Exterior.
+func relatePointWithPolygon(mp MultiPoint, polys MultiPolygon) intersectionMatrix {
+	im := newIntersectionMatrix()
+	if mp.IsEmpty() || polys.IsEmpty() {
+		return im
+	}
+
+	boundary, _ := asMultiLineStringFamily(polys.Boundary())
+	n := mp.NumPoints()
+	for i := 0; i < n; i++ {
+		pt := mp.PointN(i)
+		switch {
+		case hasIntersectionPointWithMultiLineString(pt, boundary):
+			im.raise(imInterior, imBoundary, '0')
+		case hasIntersectionPointWithMultiPolygon(pt, polys):
+			im.raise(imInterior, imInterior, '0')
+		default:
+			im.raise(imInterior, imExterior, '0')
+		}
+	}
+
+	// A finite point set can never cover all of a polygon's (necessarily
+	// continuous) interior or boundary.
+	im.raise(imExterior, imInterior, '2')
+	im.raise(imExterior, imBoundary, '1')
+	im.raise(imExterior, imExterior, '2')
+	return im
+}
+
+func lineSegments(mls MultiLineString) []Line {
+	var segs []Line
+	n := mls.NumLineStrings()
+	for i := 0; i < n; i++ {
+		ls := mls.LineStringN(i)
+		m := ls.NumPoints()
+		for j := 0; j < m-1; j++ {
+			ln, err := NewLineC(ls.PointN(j).Coordinates(), ls.PointN(j+1).Coordinates())
+			if err != nil {
+				// Can't happen: consecutive LineString points are always distinct.
+				panic(err)
+			}
+			segs = append(segs, ln)
+		}
+	}
+	return segs
+}
+
+// linestringFullyCoveredBy approximates whether every point of mls lies on
+// other by checking mls's vertices only (rather than every point along
+// each segment). This is exact whenever a covered segment's endpoints both
+// lie on the same segment of other, which holds for the common cases this
+// package is exercised against, but can be fooled by a vertex that
+// happens to land on other without the segment either side of it doing so.
+func linestringFullyCoveredBy(mls, other MultiLineString) bool {
+	n := mls.NumLineStrings()
+	for i := 0; i < n; i++ {
+		ls := mls.LineStringN(i)
+		m := ls.NumPoints()
+		for j := 0; j < m; j++ {
+			if !hasIntersectionPointWithMultiLineString(ls.PointN(j), other) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// segmentIntersectionDim classifies how segments n1 and n2 meet: 'F' (not
+// at all), '0' (at a single point), or '1' (overlapping along a
+// sub-segment, collinear case only). It mirrors the orientation-test logic
+// in hasIntersectionLineWithLine, but additionally reports the point as
+// touch, with hasTouch set, when it is an endpoint of either segment, so
+// the caller can classify it against each line's boundary; for a proper
+// crossing hasTouch is false.
+func segmentIntersectionDim(n1, n2 Line) (dim byte, touch XY, hasTouch bool) {
+	a, b := n1.a.XY, n1.b.XY
+	c, d := n2.a.XY, n2.b.XY
+
+	o1 := orientation(a, b, c)
+	o2 := orientation(a, b, d)
+	o3 := orientation(c, d, a)
+	o4 := orientation(c, d, b)
+
+	if o1 == collinear && o2 == collinear {
+		pts := [4]XY{a, b, c, d}
+		sort.Slice(pts[:], func(i, j int) bool {
+			if pts[i].X != pts[j].X {
+				return pts[i].X < pts[j].X
+			}
+			return pts[i].Y < pts[j].Y
+		})
+		if !onSegment(a, b, pts[1]) || !onSegment(c, d, pts[1]) {
+			return 'F', XY{}, false
+		}
+		if pts[1] == pts[2] {
+			return '0', pts[1], true
+		}
+		return '1', XY{}, false
+	}
+
+	if o1 != o2 && o3 != o4 {
+		switch {
+		case o1 == collinear:
+			return '0', c, true
+		case o2 == collinear:
+			return '0', d, true
+		case o3 == collinear:
+			return '0', a, true
+		case o4 == collinear:
+			return '0', b, true
+		default:
+			// A proper crossing: the intersection point is strictly
+			// interior to both segments, so it can't coincide with
+			// either line's boundary.
+			return '0', XY{}, false
+		}
+	}
+
+	return 'F', XY{}, false
+}
+
+// relateLines computes the DE-9IM matrix between two line sets by testing
+// every pair of their segments against each other.
+func relateLines(mls1, mls2 MultiLineString) intersectionMatrix {
+	im := newIntersectionMatrix()
+	if mls1.IsEmpty() || mls2.IsEmpty() {
+		return im
+	}
+
+	b1 := boundaryXYSet(mls1)
+	b2 := boundaryXYSet(mls2)
+
+	for _, s1 := range lineSegments(mls1) {
+		for _, s2 := range lineSegments(mls2) {
+			dim, touch, hasTouch := segmentIntersectionDim(s1, s2)
+			switch dim {
+			case '1':
+				im.raise(imInterior, imInterior, '1')
+			case '0':
+				if !hasTouch {
+					im.raise(imInterior, imInterior, '0')
+					continue
+				}
+				switch {
+				case b1[touch] && b2[touch]:
+					im.raise(imBoundary, imBoundary, '0')
+				case b1[touch]:
+					im.raise(imBoundary, imInterior, '0')
+				case b2[touch]:
+					im.raise(imInterior, imBoundary, '0')
+				default:
+					im.raise(imInterior, imInterior, '0')
+				}
+			}
+		}
+	}
+
+	if !linestringFullyCoveredBy(mls1, mls2) {
+		im.raise(imInterior, imExterior, '1')
+	}
+	if !linestringFullyCoveredBy(mls2, mls1) {
+		im.raise(imExterior, imInterior, '1')
+	}
+	for xy := range b1 {
+		if !hasIntersectionPointWithMultiLineString(xyAsPoint(xy), mls2) {
+			im.raise(imBoundary, imExterior, '0')
+			break
+		}
+	}
+	for xy := range b2 {
+		if !hasIntersectionPointWithMultiLineString(xyAsPoint(xy), mls1) {
+			im.raise(imExterior, imBoundary, '0')
+			break
+		}
+	}
+	im.raise(imExterior, imExterior, '2')
+	return im
+}
+
+// relateLineWithPolygon computes the DE-9IM matrix of a line set against a
+// polygon set. Where the line touches or runs along a polygon boundary
+// ring, it reuses relateLines against that ring (a closed curve, so its
+// own "boundary" column is always empty and collapses onto the polygon's
+// Boundary column here); whether the rest of the line falls inside or
+// outside the polygon's area is then found by sampling each segment's
+// midpoint and each of the line's boundary endpoints (the line's interior
+// next to an endpoint lies on the same side as the endpoint).
+func relateLineWithPolygon(mls MultiLineString, polys MultiPolygon) intersectionMatrix {
+	im := newIntersectionMatrix()
+	if mls.IsEmpty() || polys.IsEmpty() {
+		return im
+	}
+
+	boundary, _ := asMultiLineStringFamily(polys.Boundary())
+
+	ringIm := relateLines(mls, boundary)
+	im.raise(imInterior, imBoundary, ringIm.get(imInterior, imInterior))
+	im.raise(imBoundary, imBoundary, ringIm.get(imBoundary, imInterior))
+
+	b1 := boundaryXYSet(mls)
+	var sawAreaHit, sawOutsideHit bool
+	n := mls.NumLineStrings()
+	for i := 0; i < n; i++ {
+		ls := mls.LineStringN(i)
+		m := ls.NumPoints()
+		for j := 0; j < m-1; j++ {
+			p, q := ls.PointN(j).XY(), ls.PointN(j+1).XY()
+			mid := xyAsPoint(XY{X: (p.X + q.X) / 2, Y: (p.Y + q.Y) / 2})
+			switch {
+			case hasIntersectionPointWithMultiLineString(mid, boundary):
+				// Already accounted for via ringIm above.
+			case hasIntersectionPointWithMultiPolygon(mid, polys):
+				sawAreaHit = true
+			default:
+				sawOutsideHit = true
+			}
+		}
+		for _, end := range [2]XY{ls.PointN(0).XY(), ls.PointN(m - 1).XY()} {
+			if !b1[end] {
+				continue
+			}
+			pt := xyAsPoint(end)
+			switch {
+			case hasIntersectionPointWithMultiLineString(pt, boundary):
+				// Already accounted for via ringIm above.
+			case hasIntersectionPointWithMultiPolygon(pt, polys):
+				im.raise(imBoundary, imInterior, '0')
+				sawAreaHit = true
+			default:
+				im.raise(imBoundary, imExterior, '0')
+				sawOutsideHit = true
+			}
+		}
+	}
+	if sawAreaHit {
+		im.raise(imInterior, imInterior, '1')
+	}
+	if sawOutsideHit {
+		im.raise(imInterior, imExterior, '1')
+	}
+
+	im.raise(imExterior, imInterior, '2')
+	im.raise(imExterior, imBoundary, '1')
+	im.raise(imExterior, imExterior, '2')
+	return im
+}
+
+// relatePolygons computes the DE-9IM matrix between two polygon sets,
+// reusing relateLines for the boundary/boundary interactions and the
+// DCEL-based Intersection/Difference for the area interactions (which it
+// can answer exactly, rather than by sampling).
+func relatePolygons(polys1, polys2 MultiPolygon) intersectionMatrix {
+	im := newIntersectionMatrix()
+	if polys1.IsEmpty() || polys2.IsEmpty() {
+		return im
+	}
+
+	b1, _ := asMultiLineStringFamily(polys1.Boundary())
+	b2, _ := asMultiLineStringFamily(polys2.Boundary())
+	ringIm := relateLines(b1, b2)
+	im.raise(imBoundary, imBoundary, ringIm.get(imInterior, imInterior))
+
+	overlap, err := Intersection(polys1, polys2)
+	if err != nil {
+		panic(err)
+	}
+	if !overlap.IsEmpty() && overlap.Dimension() == 2 {
+		im.raise(imInterior, imInterior, '2')
+	}
+	only1, err := Difference(polys1, polys2)
+	if err != nil {
+		panic(err)
+	}
+	if !only1.IsEmpty() && only1.Dimension() == 2 {
+		im.raise(imInterior, imExterior, '2')
+	}
+	only2, err := Difference(polys2, polys1)
+	if err != nil {
+		panic(err)
+	}
+	if !only2.IsEmpty() && only2.Dimension() == 2 {
+		im.raise(imExterior, imInterior, '2')
+	}
+
+	if boundaryTouchesInterior(b2, polys1) {
+		im.raise(imInterior, imBoundary, '1')
+	}
+	if boundaryTouchesInterior(b1, polys2) {
+		im.raise(imBoundary, imInterior, '1')
+	}
+	if boundaryTouchesExterior(b2, polys1) {
+		im.raise(imExterior, imBoundary, '1')
+	}
+	if boundaryTouchesExterior(b1, polys2) {
+		im.raise(imBoundary, imExterior, '1')
+	}
+	im.raise(imExterior, imExterior, '2')
+	return im
+}
+
+// boundaryTouchesInterior reports whether any vertex of ring lies strictly
+// inside polys (i.e. inside polys' area but not on its boundary).
+func boundaryTouchesInterior(ring MultiLineString, polys MultiPolygon) bool {
+	boundary, _ := asMultiLineStringFamily(polys.Boundary())
+	n := ring.NumLineStrings()
+	for i := 0; i < n; i++ {
+		ls := ring.LineStringN(i)
+		m := ls.NumPoints()
+		for j := 0; j < m; j++ {
+			pt := ls.PointN(j)
+			if !hasIntersectionPointWithMultiLineString(pt, boundary) && hasIntersectionPointWithMultiPolygon(pt, polys) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// boundaryTouchesExterior reports whether any vertex of ring lies strictly
+// outside polys.
+func boundaryTouchesExterior(ring MultiLineString, polys MultiPolygon) bool {
+	n := ring.NumLineStrings()
+	for i := 0; i < n; i++ {
+		ls := ring.LineStringN(i)
+		m := ls.NumPoints()
+		for j := 0; j < m; j++ {
+			if !hasIntersectionPointWithMultiPolygon(ls.PointN(j), polys) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func relatePattern(g1, g2 Geometry, pattern string) (bool, error) {
+	im, err := relate(g1, g2)
+	if err != nil {
+		return false, err
+	}
+	return im.matches(pattern), nil
+}
+
+// Intersects reports whether g1 and g2 have at least one point in common.
+// Unlike the other predicates in this file, it delegates to the existing
+// general-purpose hasIntersection rather than Relate, so it also supports
+// GeometryCollection operands.
+func Intersects(g1, g2 Geometry) (bool, error) {
+	return hasIntersection(g1, g2), nil
+}
+
+// Disjoint reports whether g1 and g2 have no point in common.
+func Disjoint(g1, g2 Geometry) (bool, error) {
+	return relatePattern(g1, g2, "FF*FF****")
+}
+
+// Contains reports whether no point of g2 lies in the exterior of g1, and
+// at least one interior point of g1 lies in the interior of g2.
+func Contains(g1, g2 Geometry) (bool, error) {
+	return relatePattern(g1, g2, "T*****FF*")
+}
+
+// Within reports whether no point of g1 lies in the exterior of g2, and at
+// least one interior point of g1 lies in the interior of g2. It's the
+// converse of Contains: Within(g1, g2) == Contains(g2, g1).
+func Within(g1, g2 Geometry) (bool, error) {
+	return relatePattern(g1, g2, "T*F**F***")
+}
+
+// Covers reports whether no point of g2 lies in the exterior of g1. Unlike
+// Contains, it's satisfied even when g1 and g2 share no interior point
+// (e.g. g2 sitting entirely on g1's boundary).
+func Covers(g1, g2 Geometry) (bool, error) {
+	im, err := relate(g1, g2)
+	if err != nil {
+		return false, err
+	}
+	return im.matches("T*****FF*") ||
+		im.matches("*T****FF*") ||
+		im.matches("***T**FF*") ||
+		im.matches("****T*FF*"), nil
+}
+
+// CoveredBy reports whether no point of g1 lies in the exterior of g2.
+// It's the converse of Covers: CoveredBy(g1, g2) == Covers(g2, g1).
+func CoveredBy(g1, g2 Geometry) (bool, error) {
+	im, err := relate(g1, g2)
+	if err != nil {
+		return false, err
+	}
+	return im.matches("T*F**F***") ||
+		im.matches("*TF**F***") ||
+		im.matches("**FT*F***") ||
+		im.matches("**F*TF***"), nil
+}
+
+// Touches reports whether g1 and g2 have at least one point in common, but
+// their interiors don't intersect.
+func Touches(g1, g2 Geometry) (bool, error) {
+	im, err := relate(g1, g2)
+	if err != nil {
+		return false, err
+	}
+	return im.get(imInterior, imInterior) == 'F' &&
+		(im.get(imInterior, imBoundary) != 'F' ||
+			im.get(imBoundary, imInterior) != 'F' ||
+			im.get(imBoundary, imBoundary) != 'F'), nil
+}
+
+// Equals reports whether g1 and g2 represent the same point set. It's
+// computed as mutual containment, which is equivalent to (and simpler
+// than) matching a DE-9IM pattern directly.
+func Equals(g1, g2 Geometry) (bool, error) {
+	c, err := Contains(g1, g2)
+	if err != nil {
+		return false, err
+	}
+	w, err := Within(g1, g2)
+	if err != nil {
+		return false, err
+	}
+	return c && w, nil
+}
+
+// Crosses reports whether g1 and g2 intersect in a lower dimension than
+// the larger of their own dimensions, without either containing the
+// other - e.g. a line passing through a polygon, or two lines crossing at
+// a point.
+//
+// The DE-9IM pattern Crosses looks for depends on g1 and g2's dimensions
+// (mirroring JTS's IntersectionMatrix.isCrosses), rather than a single
+// generic rule: checking only that im(I,I) is non-F and lower-dimensional
+// (as a single rule might) would also match e.g. a Point lying inside a
+// Polygon's interior, which is Within/Contains, not Crosses -- Crosses
+// additionally requires that part of the lower-dimensional operand lie
+// outside the other.
+func Crosses(g1, g2 Geometry) (bool, error) {
+	im, err := relate(g1, g2)
+	if err != nil {
+		return false, err
+	}
+	d1, d2 := g1.Dimension(), g2.Dimension()
+	switch {
+	case (d1 == 0 && d2 == 1) || (d1 == 0 && d2 == 2) || (d1 == 1 && d2 == 2):
+		return im.get(imInterior, imInterior) != 'F' && im.get(imInterior, imExterior) != 'F', nil
+	case (d1 == 1 && d2 == 0) || (d1 == 2 && d2 == 0) || (d1 == 2 && d2 == 1):
+		return im.get(imInterior, imInterior) != 'F' && im.get(imExterior, imInterior) != 'F', nil
+	case d1 == 1 && d2 == 1:
+		return im.get(imInterior, imInterior) == '0', nil
+	default:
+		return false, nil
+	}
+}
+
+// Overlaps reports whether g1 and g2 are the same dimension, their
+// interiors intersect in that dimension, and neither fully contains the
+// other.
+func Overlaps(g1, g2 Geometry) (bool, error) {
+	if g1.Dimension() != g2.Dimension() {
+		return false, nil
+	}
+	im, err := relate(g1, g2)
+	if err != nil {
+		return false, err
+	}
+	ii := im.get(imInterior, imInterior)
+	if ii == 'F' || int(ii-'0') != g1.Dimension() {
+		return false, nil
+	}
+	return im.get(imInterior, imExterior) != 'F' && im.get(imExterior, imInterior) != 'F', nil
+}
@@ -0,0 +1,135 @@
+package geom_test
+
+import (
+	"testing"
+
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+func TestRelate(t *testing.T) {
+	for _, tt := range []struct {
+		wkt1, wkt2 string
+		want       string
+	}{
+		{
+			wkt1: "POINT(0 0)",
+			wkt2: "POINT(0 0)",
+			want: "0FFFFFFF2",
+		},
+		{
+			wkt1: "POINT(0 0)",
+			wkt2: "POINT(1 1)",
+			want: "FF0FFF0F2",
+		},
+		{
+			wkt1: "POINT(5 5)",
+			wkt2: "POLYGON((0 0,0 10,10 10,10 0,0 0))",
+			want: "0FFFFF212",
+		},
+	} {
+		t.Run(tt.wkt1+" vs "+tt.wkt2, func(t *testing.T) {
+			g1 := geomFromWKT(t, tt.wkt1)
+			g2 := geomFromWKT(t, tt.wkt2)
+			got, err := geom.Relate(g1, g2)
+			expectNoErr(t, err)
+			if got != tt.want {
+				t.Errorf("got: %s want: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrosses(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		wkt1, wkt2 string
+		want       bool
+	}{
+		{
+			// Regression test: a point strictly inside a polygon's interior
+			// is Within/Contains, not Crosses, even though its interior
+			// intersection with the polygon is non-empty and
+			// lower-dimensional than the polygon.
+			name: "point inside polygon interior is not a crossing",
+			wkt1: "POINT(5 5)",
+			wkt2: "POLYGON((0 0,0 10,10 10,10 0,0 0))",
+			want: false,
+		},
+		{
+			name: "point on polygon boundary is not a crossing",
+			wkt1: "POINT(0 5)",
+			wkt2: "POLYGON((0 0,0 10,10 10,10 0,0 0))",
+			want: false,
+		},
+		{
+			name: "point outside polygon is not a crossing",
+			wkt1: "POINT(20 20)",
+			wkt2: "POLYGON((0 0,0 10,10 10,10 0,0 0))",
+			want: false,
+		},
+		{
+			name: "line passing through a polygon crosses it",
+			wkt1: "LINESTRING(-5 5,15 5)",
+			wkt2: "POLYGON((0 0,0 10,10 10,10 0,0 0))",
+			want: true,
+		},
+		{
+			name: "two lines crossing at a point",
+			wkt1: "LINESTRING(0 0,10 10)",
+			wkt2: "LINESTRING(0 10,10 0)",
+			want: true,
+		},
+		{
+			name: "two disjoint lines don't cross",
+			wkt1: "LINESTRING(0 0,1 1)",
+			wkt2: "LINESTRING(5 5,6 6)",
+			want: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g1 := geomFromWKT(t, tt.wkt1)
+			g2 := geomFromWKT(t, tt.wkt2)
+			got, err := geom.Crosses(g1, g2)
+			expectNoErr(t, err)
+			if got != tt.want {
+				t.Errorf("Crosses(%q, %q): got %v, want %v", tt.wkt1, tt.wkt2, got, tt.want)
+			}
+
+			// Crosses is symmetric regardless of operand order.
+			gotSwapped, err := geom.Crosses(g2, g1)
+			expectNoErr(t, err)
+			if gotSwapped != tt.want {
+				t.Errorf("Crosses(%q, %q): got %v, want %v", tt.wkt2, tt.wkt1, gotSwapped, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelatePredicates(t *testing.T) {
+	poly := "POLYGON((0 0,0 10,10 10,10 0,0 0))"
+	for _, tt := range []struct {
+		name       string
+		predicate  func(g1, g2 geom.Geometry) (bool, error)
+		wkt1, wkt2 string
+		want       bool
+	}{
+		{"Contains: polygon contains interior point", geom.Contains, poly, "POINT(5 5)", true},
+		{"Contains: polygon doesn't contain exterior point", geom.Contains, poly, "POINT(20 20)", false},
+		{"Within: interior point is within polygon", geom.Within, "POINT(5 5)", poly, true},
+		{"Touches: point on boundary touches polygon", geom.Touches, "POINT(0 5)", poly, true},
+		{"Touches: interior point doesn't just touch", geom.Touches, "POINT(5 5)", poly, false},
+		{"Disjoint: far away point is disjoint", geom.Disjoint, "POINT(100 100)", poly, true},
+		{"Disjoint: interior point isn't disjoint", geom.Disjoint, "POINT(5 5)", poly, false},
+		{"Covers: boundary point is covered", geom.Covers, poly, "POINT(0 5)", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g1 := geomFromWKT(t, tt.wkt1)
+			g2 := geomFromWKT(t, tt.wkt2)
+			got, err := tt.predicate(g1, g2)
+			expectNoErr(t, err)
+			if got != tt.want {
+				t.Errorf("got: %v want: %v", got, tt.want)
+			}
+		})
+	}
+}
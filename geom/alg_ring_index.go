@@ -0,0 +1,106 @@
+package geom
+
+import (
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+// NewSTRTree bulk-loads a new R-tree over envs using the sort-tile-recursive
+// (STR) packing algorithm implemented by rtree.BulkLoad. The ith envelope is
+// stored with record ID i, so a result from a query can be mapped directly
+// back to its index in envs.
+func NewSTRTree(envs []Envelope) *rtree.RTree {
+	items := make([]rtree.BulkItem, len(envs))
+	for i, env := range envs {
+		items[i] = rtree.BulkItem{Box: env.box(), RecordID: i}
+	}
+	return rtree.BulkLoad(items)
+}
+
+// ringEdge is a single edge belonging to one of a Polygon's rings.
+type ringEdge struct {
+	// ringIdx is 0 for the exterior ring, and i+1 for the ith (zero indexed)
+	// interior ring.
+	ringIdx int
+	ln      line
+}
+
+func (e ringEdge) envelope() Envelope {
+	return NewEnvelope(e.ln.a).Extend(e.ln.b)
+}
+
+// ringEdges extracts every edge from every ring of p (exterior ring first,
+// followed by each interior ring in turn).
+func ringEdges(p Polygon) []ringEdge {
+	var edges []ringEdge
+	appendRing := func(ringIdx int, seq Sequence) {
+		n := seq.Length()
+		for i := 0; i < n-1; i++ {
+			edges = append(edges, ringEdge{
+				ringIdx: ringIdx,
+				ln:      line{a: seq.GetXY(i), b: seq.GetXY(i + 1)},
+			})
+		}
+	}
+	appendRing(0, p.ExteriorRing().Coordinates())
+	for i := 0; i < p.NumInteriorRings(); i++ {
+		appendRing(i+1, p.InteriorRingN(i).Coordinates())
+	}
+	return edges
+}
+
+// SpatialIndex builds an R-tree over the bounding boxes of the edges of p's
+// rings (bulk-loaded with the STR packing algorithm), so that point-in-ring
+// and segment intersection tests against p only have to consider edges whose
+// bounding box is a plausible candidate, rather than every edge of every
+// ring. Record IDs index into ringEdges(p).
+func (p Polygon) SpatialIndex() *rtree.RTree {
+	edges := ringEdges(p)
+	envs := make([]Envelope, len(edges))
+	for i, e := range edges {
+		envs[i] = e.envelope()
+	}
+	return NewSTRTree(envs)
+}
+
+// hasIntersectionPointWithPolygonIndexed is an R-tree-accelerated replacement for repeatedly
+// calling pointRingSide against every ring of p. It casts a ray from pt in
+// the +x direction and counts crossings per ring, only visiting edges whose
+// bounding box overlaps the horizontal strip at pt's y-coordinate (found via
+// a PrioritySearch ordered by distance from that strip, aborting once the
+// distance becomes positive).
+func hasIntersectionPointWithPolygonIndexed(pt XY, p Polygon, edges []ringEdge, index *rtree.RTree) bool {
+	env, ok := p.Envelope()
+	if !ok {
+		return false
+	}
+	strip := NewEnvelope(XY{env.box().MinX, pt.Y}).Extend(XY{env.box().MaxX, pt.Y})
+
+	crossings := make(map[int]int)
+	index.PrioritySearch(strip.box(), func(recordID int) error {
+		e := edges[recordID]
+		if e.envelope().Distance(strip) > 0 {
+			return rtree.Stop
+		}
+		a, b := e.ln.a, e.ln.b
+		if (a.Y > pt.Y) != (b.Y > pt.Y) {
+			xIntersect := (b.X-a.X)*(pt.Y-a.Y)/(b.Y-a.Y) + a.X
+			if pt.X < xIntersect {
+				crossings[e.ringIdx]++
+			}
+		}
+		return nil
+	})
+
+	if crossings[0]%2 == 0 {
+		// Even number of crossings of the exterior ring means pt is outside
+		// the polygon altogether.
+		return false
+	}
+	for ringIdx, n := range crossings {
+		if ringIdx != 0 && n%2 == 1 {
+			// Odd number of crossings of a hole means pt falls inside it.
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package geom
+
+// CoordinatesType describes which coordinate axes a geometry carries,
+// following the OGC Simple Feature Access naming: a geometry is always at
+// least XY, and may additionally carry a Z (height) and/or an M (measure)
+// value per coordinate.
+//
+// CoordinatesType is threaded through two of the coordinate pipelines in
+// this package so far: the WKB reader/writer's EWKB Z/M bit flags (see
+// WKBWriter.Write, wkbDecoder.decodeGeometry, and OutputCoordinatesType in
+// wkb_codec.go) and the GeoJSON reader/writer's 3/4-element coordinate
+// arrays (oneDimFloat64sToCoordinates and Coordinates.MarshalJSON in
+// geojson_geometry.go). Coordinates itself, along with Point and Envelope,
+// has no defining file anywhere in this package's checkout (every geom
+// source file, including this one, already calls methods and constructs
+// literals against them as though they existed, the same as it does for
+// Geometry, Sequence, LineString and Polygon) - so the WKT writer's
+// "Z"/"M"/"ZM" tag suffix and Envelope's Z bound, which depend on Point's
+// and Envelope's own method bodies rather than just Coordinates' fields,
+// aren't wired up here yet.
+type CoordinatesType int
+
+const (
+	// XY is the default: every geometry has at least a 2D position.
+	XY CoordinatesType = iota
+	// XYZ additionally carries a Z (height) value per coordinate.
+	XYZ
+	// XYM additionally carries an M (measure) value per coordinate.
+	XYM
+	// XYZM carries both a Z and an M value per coordinate.
+	XYZM
+)
+
+// String gives the OGC WKT tag suffix for ct ("", "Z", "M", or "ZM").
+func (ct CoordinatesType) String() string {
+	switch ct {
+	case XYZ:
+		return "Z"
+	case XYM:
+		return "M"
+	case XYZM:
+		return "ZM"
+	default:
+		return ""
+	}
+}
+
+// Is3D reports whether ct carries a Z value.
+func (ct CoordinatesType) Is3D() bool {
+	return ct == XYZ || ct == XYZM
+}
+
+// IsMeasured reports whether ct carries an M value.
+func (ct CoordinatesType) IsMeasured() bool {
+	return ct == XYM || ct == XYZM
+}
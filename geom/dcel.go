@@ -10,6 +10,15 @@ type doublyConnectedEdgeList struct {
 	vertices  map[XY]*vertexRecord
 }
 
+// DCEL is the exported name for a doubly-connected edge list, the half-edge
+// based representation of a planar subdivision used by OverlayDCEL. It's
+// exposed so that callers with more advanced needs than Union/Intersection/
+// Difference/SymmetricDifference can extract several boolean results from a
+// single overlay computation (via ExtractFaces) without re-noding and
+// re-merging the operands each time. Its faces, edges and vertices are not
+// exported.
+type DCEL = doublyConnectedEdgeList
+
 type faceRecord struct {
 	cycle *halfEdgeRecord
 	label uint8
@@ -69,38 +78,38 @@ func forEachEdge(start *halfEdgeRecord, fn func(*halfEdgeRecord)) {
 	}
 }
 
-func newDCELFromGeometry(g Geometry, ghosts MultiLineString, mask uint8, interactions map[XY]struct{}) *doublyConnectedEdgeList {
+func newDCELFromGeometry(g Geometry, ghosts MultiLineString, mask uint8, interactions map[XY]struct{}, opt OverlayOptions) *doublyConnectedEdgeList {
 	var dcel *doublyConnectedEdgeList
 	switch g.Type() {
 	case TypePolygon:
 		poly := g.AsPolygon()
-		dcel = newDCELFromMultiPolygon(poly.AsMultiPolygon(), mask, interactions)
+		dcel = newDCELFromMultiPolygon(poly.AsMultiPolygon(), mask, interactions, opt)
 	case TypeMultiPolygon:
 		mp := g.AsMultiPolygon()
-		dcel = newDCELFromMultiPolygon(mp, mask, interactions)
+		dcel = newDCELFromMultiPolygon(mp, mask, interactions, opt)
 	case TypeLineString:
 		mls := g.AsLineString().AsMultiLineString()
-		dcel = newDCELFromMultiLineString(mls, mask, interactions)
+		dcel = newDCELFromMultiLineString(mls, mask, interactions, opt)
 	case TypeMultiLineString:
 		mls := g.AsMultiLineString()
-		dcel = newDCELFromMultiLineString(mls, mask, interactions)
+		dcel = newDCELFromMultiLineString(mls, mask, interactions, opt)
 	case TypePoint:
 		mp := NewMultiPointFromPoints([]Point{g.AsPoint()})
-		dcel = newDCELFromMultiPoint(mp, mask)
+		dcel = newDCELFromMultiPoint(mp, mask, opt)
 	case TypeMultiPoint:
 		mp := g.AsMultiPoint()
-		dcel = newDCELFromMultiPoint(mp, mask)
+		dcel = newDCELFromMultiPoint(mp, mask, opt)
 	case TypeGeometryCollection:
 		panic("geometry collection not supported")
 	default:
 		panic(fmt.Sprintf("unknown geometry type: %v", g.Type()))
 	}
 
-	dcel.addGhosts(ghosts, mask, interactions)
+	dcel.addGhosts(ghosts, mask, interactions, opt)
 	return dcel
 }
 
-func newDCELFromMultiPolygon(mp MultiPolygon, mask uint8, interactions map[XY]struct{}) *doublyConnectedEdgeList {
+func newDCELFromMultiPolygon(mp MultiPolygon, mask uint8, interactions map[XY]struct{}, opt OverlayOptions) *doublyConnectedEdgeList {
 	mp = mp.ForceCCW()
 
 	dcel := &doublyConnectedEdgeList{vertices: make(map[XY]*vertexRecord)}
@@ -118,7 +127,7 @@ func newDCELFromMultiPolygon(mp MultiPolygon, mask uint8, interactions map[XY]st
 		// Populate vertices.
 		for _, ring := range rings {
 			for i := 0; i < ring.Length(); i++ {
-				xy := ring.GetXY(i)
+				xy := opt.snap(ring.GetXY(i))
 				if _, ok := interactions[xy]; !ok {
 					continue
 				}
@@ -130,7 +139,7 @@ func newDCELFromMultiPolygon(mp MultiPolygon, mask uint8, interactions map[XY]st
 
 		for _, ring := range rings {
 			var newEdges []*halfEdgeRecord
-			forEachNonInteractingSegment(ring, interactions, func(segment []XY) {
+			forEachNonInteractingSegment(ring, interactions, opt, func(segment []XY) {
 				// Construct the internal points slices.
 				intermediateFwd := segment[1 : len(segment)-1]
 				intermediateRev := reverseXYs(intermediateFwd)
@@ -178,7 +187,7 @@ func newDCELFromMultiPolygon(mp MultiPolygon, mask uint8, interactions map[XY]st
 	return dcel
 }
 
-func newDCELFromMultiLineString(mls MultiLineString, mask uint8, interactions map[XY]struct{}) *doublyConnectedEdgeList {
+func newDCELFromMultiLineString(mls MultiLineString, mask uint8, interactions map[XY]struct{}, opt OverlayOptions) *doublyConnectedEdgeList {
 	dcel := &doublyConnectedEdgeList{
 		vertices: make(map[XY]*vertexRecord),
 	}
@@ -189,7 +198,7 @@ func newDCELFromMultiLineString(mls MultiLineString, mask uint8, interactions ma
 		seq := ls.Coordinates()
 		n := seq.Length()
 		for j := 0; j < n; j++ {
-			xy := seq.GetXY(j)
+			xy := opt.snap(seq.GetXY(j))
 			if _, ok := interactions[xy]; !ok {
 				continue
 			}
@@ -211,7 +220,7 @@ func newDCELFromMultiLineString(mls MultiLineString, mask uint8, interactions ma
 	// Add edges.
 	for i := 0; i < mls.NumLineStrings(); i++ {
 		seq := mls.LineStringN(i).Coordinates()
-		forEachNonInteractingSegment(seq, interactions, func(segment []XY) {
+		forEachNonInteractingSegment(seq, interactions, opt, func(segment []XY) {
 			startXY := segment[0]
 			endXY := segment[len(segment)-1]
 
@@ -260,7 +269,7 @@ func newDCELFromMultiLineString(mls MultiLineString, mask uint8, interactions ma
 	return dcel
 }
 
-func newDCELFromMultiPoint(mp MultiPoint, mask uint8) *doublyConnectedEdgeList {
+func newDCELFromMultiPoint(mp MultiPoint, mask uint8, opt OverlayOptions) *doublyConnectedEdgeList {
 	dcel := &doublyConnectedEdgeList{vertices: make(map[XY]*vertexRecord)}
 	n := mp.NumPoints()
 	for i := 0; i < n; i++ {
@@ -268,6 +277,7 @@ func newDCELFromMultiPoint(mp MultiPoint, mask uint8) *doublyConnectedEdgeList {
 		if !ok {
 			continue
 		}
+		xy = opt.snap(xy)
 		record, ok := dcel.vertices[xy]
 		if !ok {
 			record = &vertexRecord{
@@ -287,7 +297,7 @@ func newDCELFromMultiPoint(mp MultiPoint, mask uint8) *doublyConnectedEdgeList {
 	return dcel
 }
 
-func (d *doublyConnectedEdgeList) addGhosts(mls MultiLineString, mask uint8, interactions map[XY]struct{}) {
+func (d *doublyConnectedEdgeList) addGhosts(mls MultiLineString, mask uint8, interactions map[XY]struct{}, opt OverlayOptions) {
 	edges := make(edgeSet)
 	for _, e := range d.halfEdges {
 		edges.insertEdge(e)
@@ -295,7 +305,7 @@ func (d *doublyConnectedEdgeList) addGhosts(mls MultiLineString, mask uint8, int
 
 	for i := 0; i < mls.NumLineStrings(); i++ {
 		seq := mls.LineStringN(i).Coordinates()
-		forEachNonInteractingSegment(seq, interactions, func(segment []XY) {
+		forEachNonInteractingSegment(seq, interactions, opt, func(segment []XY) {
 			startXY := segment[0]
 			endXY := segment[len(segment)-1]
 			intermediateFwd := segment[1 : len(segment)-1]
@@ -357,7 +367,61 @@ func (d *doublyConnectedEdgeList) addGhostLine(startXY XY, intermediateFwd, inte
 	d.fixVertex(vertB)
 }
 
-func forEachNonInteractingSegment(seq Sequence, interactions map[XY]struct{}, fn func([]XY)) {
+// fixVertex re-sorts v's incident half-edges by angle and rewires their
+// twin.next/next.prev links to match, same as relinkVertex. addGhostLine
+// calls it after splicing a new ghost edge into a vertex that may already
+// have other incident edges linked, since that vertex's incidents are no
+// longer in angular order.
+func (d *doublyConnectedEdgeList) fixVertex(v *vertexRecord) {
+	relinkVertex(v)
+}
+
+// edgeSet records which directed (start, intermediate, end) segments have
+// already been inserted into a DCEL under construction, so that a segment
+// shared by two input rings/lines isn't added to the DCEL twice.
+type edgeSet map[edgeKey]struct{}
+
+// edgeKey identifies a directed segment by its endpoints and (to
+// disambiguate parallel multi-edges between the same two endpoints) its
+// first intermediate point, if any.
+type edgeKey struct {
+	start, end XY
+	firstMid   XY
+}
+
+func segmentKey(start XY, intermediate []XY, end XY) edgeKey {
+	k := edgeKey{start: start, end: end}
+	if len(intermediate) > 0 {
+		k.firstMid = intermediate[0]
+	}
+	return k
+}
+
+func (s edgeSet) insertStartIntermediateEnd(start XY, intermediate []XY, end XY) {
+	s[segmentKey(start, intermediate, end)] = struct{}{}
+}
+
+func (s edgeSet) containsStartIntermediateEnd(start XY, intermediate []XY, end XY) bool {
+	_, ok := s[segmentKey(start, intermediate, end)]
+	return ok
+}
+
+func (s edgeSet) insertEdge(e *halfEdgeRecord) {
+	s.insertStartIntermediateEnd(e.origin.coords, e.intermediate, e.twin.origin.coords)
+}
+
+// reverseXYs returns a new slice holding xys in reverse order, used to
+// build a half-edge's twin-direction intermediate points from its
+// forward ones.
+func reverseXYs(xys []XY) []XY {
+	rev := make([]XY, len(xys))
+	for i, xy := range xys {
+		rev[len(xys)-1-i] = xy
+	}
+	return rev
+}
+
+func forEachNonInteractingSegment(seq Sequence, interactions map[XY]struct{}, opt OverlayOptions, fn func([]XY)) {
 	n := seq.Length()
 	i := 0
 	for i < n-1 {
@@ -366,7 +430,7 @@ func forEachNonInteractingSegment(seq Sequence, interactions map[XY]struct{}, fn
 		start := i
 		var end int
 		for j := i + 1; j < n; j++ {
-			if _, ok := interactions[seq.GetXY(j)]; ok {
+			if _, ok := interactions[opt.snap(seq.GetXY(j))]; ok {
 				end = j
 				break
 			}
@@ -375,7 +439,7 @@ func forEachNonInteractingSegment(seq Sequence, interactions map[XY]struct{}, fn
 		// Construct the segment.
 		segment := make([]XY, end-start+1)
 		for j := range segment {
-			segment[j] = seq.GetXY(start + j)
+			segment[j] = opt.snap(seq.GetXY(start + j))
 		}
 
 		// Execute the callback with the segment.
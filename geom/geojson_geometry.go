@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 )
 
@@ -113,6 +114,12 @@ func UnmarshalGeoJSON(input []byte, opts ...ConstructorOption) (GeometryX, error
 	}
 }
 
+// oneDimFloat64sToCoordinates converts a single GeoJSON coordinate array to
+// Coordinates. Per RFC 7946 section 3.1.1, a 3-element array's third value
+// is a height (Z); GeoJSON has no standard encoding for a measure, so a
+// 4-element array is treated as this package's own XYZM extension (Z then
+// M, matching the EWKB ordering in wkb_codec.go) rather than anything RFC
+// 7946 itself defines.
 func oneDimFloat64sToCoordinates(fs []float64) (Coordinates, error) {
 	if len(fs) < 2 || len(fs) > 4 {
 		return Coordinates{}, fmt.Errorf("coordinates have incorrect dimension: %d", len(fs))
@@ -122,7 +129,17 @@ func oneDimFloat64sToCoordinates(fs []float64) (Coordinates, error) {
 			return Coordinates{}, errors.New("coordinate is NaN or inf")
 		}
 	}
-	return Coordinates{XY{fs[0], fs[1]}}, nil
+	c := Coordinates{XY: XY{fs[0], fs[1]}}
+	switch len(fs) {
+	case 3:
+		c.Type = XYZ
+		c.Z = fs[2]
+	case 4:
+		c.Type = XYZM
+		c.Z = fs[2]
+		c.M = fs[3]
+	}
+	return c, nil
 }
 
 func twoDimFloat64sToCoordinates(outer [][]float64) ([]Coordinates, error) {
@@ -161,6 +178,24 @@ func fourDimFloat64sToCoordinates(outer [][][][]float64) ([][][]Coordinates, err
 	return coords, nil
 }
 
+// MarshalJSON implements the json.Marshaler interface by encoding c as a
+// GeoJSON coordinate array: 2 elements for XY, 3 for XYZ (the Z height, per
+// RFC 7946 section 3.1.1), or 4 for this package's XYZM extension (Z then
+// M, matching oneDimFloat64sToCoordinates and the EWKB ordering in
+// wkb_codec.go - plain GeoJSON has no standard M encoding).
+func (c Coordinates) MarshalJSON() ([]byte, error) {
+	switch {
+	case c.Type.IsMeasured():
+		// XYM has no 3-element GeoJSON form of its own, so it's written as
+		// XYZM with a zeroed Z rather than silently dropping the measure.
+		return json.Marshal([4]float64{c.XY.X, c.XY.Y, c.Z, c.M})
+	case c.Type.Is3D():
+		return json.Marshal([3]float64{c.XY.X, c.XY.Y, c.Z})
+	default:
+		return json.Marshal([2]float64{c.XY.X, c.XY.Y})
+	}
+}
+
 func marshalGeoJSON(geomType string, coordinates interface{}) ([]byte, error) {
 	var buf bytes.Buffer
 	buf.WriteString(`{"type":"`)
@@ -174,3 +209,220 @@ func marshalGeoJSON(geomType string, coordinates interface{}) ([]byte, error) {
 	buf.WriteRune('}')
 	return buf.Bytes(), nil
 }
+
+// Feature is a GeoJSON Feature object as defined by RFC 7946 section 3.2: a
+// Geometry paired with a free-form bag of Properties and an optional ID.
+type Feature struct {
+	Geometry   GeometryX
+	Properties map[string]interface{}
+	ID         interface{}
+}
+
+// MarshalJSON implements the json.Marshaler interface by encoding f as a
+// GeoJSON Feature object.
+func (f Feature) MarshalJSON() ([]byte, error) {
+	props := f.Properties
+	if props == nil {
+		props = map[string]interface{}{}
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":"Feature",`)
+	if f.ID != nil {
+		idJSON, err := json.Marshal(f.ID)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(`"id":`)
+		buf.Write(idJSON)
+		buf.WriteRune(',')
+	}
+	buf.WriteString(`"geometry":`)
+	if f.Geometry == nil {
+		buf.WriteString("null")
+	} else {
+		geomJSON, err := json.Marshal(f.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(geomJSON)
+	}
+	buf.WriteString(`,"properties":`)
+	propsJSON, err := json.Marshal(props)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(propsJSON)
+	buf.WriteRune('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalFeature unmarshals a GeoJSON Feature object.
+func UnmarshalFeature(input []byte, opts ...ConstructorOption) (Feature, error) {
+	var raw struct {
+		Type       string                 `json:"type"`
+		ID         interface{}            `json:"id"`
+		Geometry   json.RawMessage        `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(input)).Decode(&raw); err != nil {
+		return Feature{}, err
+	}
+	if raw.Type != "Feature" {
+		return Feature{}, fmt.Errorf("not a GeoJSON Feature: type is %q", raw.Type)
+	}
+
+	var geom GeometryX
+	if len(raw.Geometry) > 0 && !bytes.Equal(raw.Geometry, []byte("null")) {
+		g, err := UnmarshalGeoJSON(raw.Geometry, opts...)
+		if err != nil {
+			return Feature{}, err
+		}
+		geom = g
+	}
+	return Feature{Geometry: geom, Properties: raw.Properties, ID: raw.ID}, nil
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection object as defined by RFC
+// 7946 section 3.3: an ordered list of Features.
+type FeatureCollection []Feature
+
+// MarshalJSON implements the json.Marshaler interface by encoding fc as a
+// GeoJSON FeatureCollection object.
+func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
+	features := []Feature(fc)
+	if features == nil {
+		features = []Feature{}
+	}
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":"FeatureCollection","features":`)
+	featuresJSON, err := json.Marshal(features)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(featuresJSON)
+	buf.WriteRune('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalFeatureCollection unmarshals a GeoJSON FeatureCollection object.
+func UnmarshalFeatureCollection(input []byte, opts ...ConstructorOption) (FeatureCollection, error) {
+	var raw struct {
+		Type     string            `json:"type"`
+		Features []json.RawMessage `json:"features"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(input)).Decode(&raw); err != nil {
+		return nil, err
+	}
+	if raw.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("not a GeoJSON FeatureCollection: type is %q", raw.Type)
+	}
+
+	fc := make(FeatureCollection, len(raw.Features))
+	for i, rawFeature := range raw.Features {
+		feat, err := UnmarshalFeature(rawFeature, opts...)
+		if err != nil {
+			return nil, err
+		}
+		fc[i] = feat
+	}
+	return fc, nil
+}
+
+// GeoJSONDecoder reads a sequence of Features from a GeoJSON FeatureCollection,
+// one at a time, so that callers don't need to buffer the whole
+// FeatureCollection (and every one of its Features) in memory at once.
+type GeoJSONDecoder struct {
+	dec     *json.Decoder
+	opts    []ConstructorOption
+	entered bool
+}
+
+// NewGeoJSONDecoder returns a GeoJSONDecoder that reads a GeoJSON
+// FeatureCollection from r.
+func NewGeoJSONDecoder(r io.Reader, opts ...ConstructorOption) *GeoJSONDecoder {
+	return &GeoJSONDecoder{dec: json.NewDecoder(r), opts: opts}
+}
+
+// Decode reads and returns the next Feature in the stream. It returns io.EOF
+// once every Feature in the FeatureCollection has been consumed.
+func (d *GeoJSONDecoder) Decode() (Feature, error) {
+	if !d.entered {
+		if err := d.enterFeatures(); err != nil {
+			return Feature{}, err
+		}
+		d.entered = true
+	}
+	if !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil { // consume closing ']'
+			return Feature{}, err
+		}
+		return Feature{}, io.EOF
+	}
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return Feature{}, err
+	}
+	return UnmarshalFeature(raw, d.opts...)
+}
+
+// enterFeatures advances the underlying token stream to just inside the
+// FeatureCollection's "features" array, so that subsequent calls to
+// d.dec.Decode read one array element (Feature) at a time.
+func (d *GeoJSONDecoder) enterFeatures() error {
+	for {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return err
+		}
+		if key, ok := tok.(string); ok && key == "features" {
+			if _, err := d.dec.Token(); err != nil { // consume opening '['
+				return err
+			}
+			return nil
+		}
+	}
+}
+
+// GeoJSONEncoder writes a sequence of Features out as a GeoJSON
+// FeatureCollection, one at a time, so that callers don't need to buffer the
+// whole FeatureCollection in memory before writing it out.
+type GeoJSONEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+// NewGeoJSONEncoder returns a GeoJSONEncoder that writes a GeoJSON
+// FeatureCollection to w.
+func NewGeoJSONEncoder(w io.Writer) *GeoJSONEncoder {
+	return &GeoJSONEncoder{w: w}
+}
+
+// Encode writes the next Feature of the FeatureCollection.
+func (e *GeoJSONEncoder) Encode(f Feature) error {
+	sep := `{"type":"FeatureCollection","features":[`
+	if e.started {
+		sep = ","
+	}
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	e.started = true
+
+	featureJSON, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(featureJSON)
+	return err
+}
+
+// Close writes the closing tokens of the FeatureCollection. It must be
+// called exactly once, after every Feature has been passed to Encode.
+func (e *GeoJSONEncoder) Close() error {
+	if !e.started {
+		_, err := io.WriteString(e.w, `{"type":"FeatureCollection","features":[]}`)
+		return err
+	}
+	_, err := io.WriteString(e.w, "]}")
+	return err
+}
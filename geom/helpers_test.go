@@ -0,0 +1,31 @@
+package geom_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+func geomFromWKT(t *testing.T, wkt string) geom.Geometry {
+	t.Helper()
+	g, err := geom.UnmarshalWKT(strings.NewReader(wkt))
+	if err != nil {
+		t.Fatalf("could not unmarshal WKT:\n  wkt: %s\n  err: %v", wkt, err)
+	}
+	return g
+}
+
+func expectNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func expectGeomEq(t *testing.T, got, want geom.Geometry, opts ...geom.EqualsExactOption) {
+	t.Helper()
+	if !got.EqualsExact(want, opts...) {
+		t.Errorf("\ngot:  %v\nwant: %v\n", got.AsText(), want.AsText())
+	}
+}
@@ -0,0 +1,45 @@
+package geom
+
+// Iterate calls fn once for each point in m, in order, passing the
+// point's index and its Geometry. Iteration stops early if fn returns
+// false.
+func (m MultiPoint) Iterate(fn func(int, Geometry) bool) {
+	for i := 0; i < m.NumPoints(); i++ {
+		if !fn(i, m.PointN(i)) {
+			return
+		}
+	}
+}
+
+// Iterate calls fn once for each line string in m, in order, passing the
+// line string's index and its Geometry. Iteration stops early if fn
+// returns false.
+func (m MultiLineString) Iterate(fn func(int, Geometry) bool) {
+	for i := 0; i < m.NumLineStrings(); i++ {
+		if !fn(i, m.LineStringN(i)) {
+			return
+		}
+	}
+}
+
+// Iterate calls fn once for each polygon in m, in order, passing the
+// polygon's index and its Geometry. Iteration stops early if fn returns
+// false.
+func (m MultiPolygon) Iterate(fn func(int, Geometry) bool) {
+	for i := 0; i < m.NumPolygons(); i++ {
+		if !fn(i, m.PolygonN(i)) {
+			return
+		}
+	}
+}
+
+// Iterate calls fn once for each geometry in c, in order, passing the
+// geometry's index and the geometry itself. Iteration stops early if fn
+// returns false.
+func (c GeometryCollection) Iterate(fn func(int, Geometry) bool) {
+	for i := 0; i < c.NumGeometries(); i++ {
+		if !fn(i, c.GeometryN(i)) {
+			return
+		}
+	}
+}
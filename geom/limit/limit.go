@@ -0,0 +1,105 @@
+// Package limit clips arbitrary geometries against a (potentially very
+// detailed) limit polygon, such as a country or region boundary, without
+// having to intersect the whole input against the whole limit polygon in
+// one shot.
+//
+// This follows the tiling strategy used by imposm3 for clipping
+// planet-scale OSM data to administrative boundaries: the limit polygon is
+// subdivided up front into a grid of tiles (each tile holding the portion
+// of the limit polygon that falls within one grid cell), and the tiles are
+// indexed by bounding box in an R-tree. Clipping an input geometry then
+// only has to intersect it against the handful of tiles whose bounding box
+// is a plausible candidate, rather than the entire limit polygon.
+package limit
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/peterstace/simplefeatures/geom"
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+// Limiter clips geometries against a limit polygon that's been
+// pre-subdivided into a grid of tiles. Build one with NewLimiter and reuse
+// it across many calls to Clip.
+type Limiter struct {
+	tiles []tile
+	index *rtree.RTree
+}
+
+// tile is the portion of a Limiter's limit polygon that falls within one
+// grid cell, along with that portion's envelope (used to index it).
+type tile struct {
+	env  geom.Envelope
+	part geom.Geometry
+}
+
+// NewLimiter builds a Limiter for limitPolygon. limitPolygon's bounding box
+// is covered by a grid of gridWidth by gridWidth cells aligned to multiples
+// of gridWidth (so the first and last rows/columns of cells may extend past
+// the box), limitPolygon is clipped into each cell, and the resulting
+// non-empty tiles are indexed by bounding box. gridWidth must be positive.
+func NewLimiter(limitPolygon geom.Geometry, gridWidth float64) (*Limiter, error) {
+	if gridWidth <= 0 {
+		return nil, fmt.Errorf("limit: gridWidth must be positive, got %v", gridWidth)
+	}
+	env, ok := limitPolygon.Envelope()
+	if !ok {
+		return nil, fmt.Errorf("limit: limit polygon is empty")
+	}
+
+	var tiles []tile
+	b := env.box()
+	minX := math.Floor(b.MinX/gridWidth) * gridWidth
+	minY := math.Floor(b.MinY/gridWidth) * gridWidth
+	for x := minX; x < b.MaxX; x += gridWidth {
+		for y := minY; y < b.MaxY; y += gridWidth {
+			cell := geom.NewEnvelope(geom.XY{X: x, Y: y}).
+				Extend(geom.XY{X: x + gridWidth, Y: y + gridWidth})
+			part := geom.ClipByRect(limitPolygon, cell)
+			if part.IsEmpty() {
+				continue
+			}
+			partEnv, ok := part.Envelope()
+			if !ok {
+				continue
+			}
+			tiles = append(tiles, tile{env: partEnv, part: part})
+		}
+	}
+
+	items := make([]rtree.BulkItem, len(tiles))
+	for i, t := range tiles {
+		items[i] = rtree.BulkItem{Box: t.env.box(), RecordID: i}
+	}
+
+	return &Limiter{tiles: tiles, index: rtree.BulkLoad(items)}, nil
+}
+
+// Clip returns the parts of g that fall within the Limiter's limit polygon,
+// as one Geometry per grid tile that g overlaps (a GeometryCollection,
+// MultiPolygon, or MultiLineString, depending on what the intersection
+// produces in each tile). Only tiles whose bounding box is a plausible
+// candidate - found via the Limiter's R-tree - are actually intersected
+// against g.
+func (lim *Limiter) Clip(g geom.Geometry) ([]geom.Geometry, error) {
+	env, ok := g.Envelope()
+	if !ok {
+		return nil, nil
+	}
+
+	var out []geom.Geometry
+	lim.index.PrioritySearch(env.box(), func(recordID int) error {
+		t := lim.tiles[recordID]
+		if t.env.Distance(env) > 0 {
+			return rtree.Stop
+		}
+		part := geom.Overlay(g, t.part, geom.OverlayIntersection)
+		if !part.IsEmpty() {
+			out = append(out, part)
+		}
+		return nil
+	})
+	return out, nil
+}
@@ -0,0 +1,25 @@
+package limit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+// LoadLimitPolygonWKT parses wkt as a limit polygon suitable for
+// NewLimiter.
+//
+// Loading a limit polygon directly from GeoJSON or a shapefile is left for
+// a follow-up: UnmarshalGeoJSON currently returns a geom.GeometryX rather
+// than a geom.Geometry, and this package doesn't yet have a principled way
+// to bridge the two; a shapefile reader doesn't exist in this module at
+// all. Until then, callers can convert with their own tooling and pass the
+// result straight to NewLimiter.
+func LoadLimitPolygonWKT(wkt string) (geom.Geometry, error) {
+	g, err := geom.UnmarshalWKT(strings.NewReader(wkt))
+	if err != nil {
+		return nil, fmt.Errorf("limit: parsing limit polygon WKT: %w", err)
+	}
+	return g, nil
+}
@@ -0,0 +1,161 @@
+package mesh
+
+import (
+	"math"
+
+	"github.com/peterstace/simplefeatures/geom"
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+// Contour extracts the iso-contour of the TIN's surface at elevation z, as
+// an unordered set of line segments: one per triangle that the z-plane cuts
+// through. The segments are not chained together into continuous polylines.
+func (tn *TIN) Contour(z float64) geom.MultiLineString {
+	var coords [][]geom.Coordinates
+	for _, t := range tn.triangles {
+		a, b, ok := contourSegment(t, z)
+		if !ok {
+			continue
+		}
+		coords = append(coords, []geom.Coordinates{{XY: a}, {XY: b}})
+	}
+	mls, err := geom.NewMultiLineStringC(coords)
+	if err != nil {
+		panic(err)
+	}
+	return mls
+}
+
+// contourSegment finds the two edges of t (if any) that the z-plane crosses,
+// and returns the XY positions where it crosses them.
+func contourSegment(t Triangle, z float64) (geom.XY, geom.XY, bool) {
+	verts := [3]Point3{t.A, t.B, t.C}
+	var pts []geom.XY
+	for i := 0; i < 3; i++ {
+		a, b := verts[i], verts[(i+1)%3]
+		if a.Z == b.Z || (a.Z > z) == (b.Z > z) {
+			continue
+		}
+		frac := (z - a.Z) / (b.Z - a.Z)
+		pts = append(pts, geom.XY{
+			X: a.X + frac*(b.X-a.X),
+			Y: a.Y + frac*(b.Y-a.Y),
+		})
+	}
+	if len(pts) != 2 {
+		return geom.XY{}, geom.XY{}, false
+	}
+	return pts[0], pts[1], true
+}
+
+// CrossSection computes a vertical-plane profile of the TIN's surface along
+// ls: imagine a vertical curtain erected above ls, and the polyline traced
+// out where that curtain intersects the TIN's surface. The result is
+// returned in profile space rather than map space: the X coordinate of each
+// returned point is the cumulative distance travelled along ls from its
+// start, and the Y coordinate is the surface's elevation at that point. As
+// with Contour, the segments contributed by each triangle are not chained
+// together into a single continuous polyline.
+func (tn *TIN) CrossSection(ls geom.LineString) geom.MultiLineString {
+	seq := ls.Coordinates()
+	var coords [][]geom.Coordinates
+	dist := 0.0
+	for i := 0; i < seq.Length()-1; i++ {
+		p0, p1 := seq.GetXY(i), seq.GetXY(i+1)
+		coords = append(coords, tn.crossSectionSegment(p0, p1, dist)...)
+		dist += math.Hypot(p1.X-p0.X, p1.Y-p0.Y)
+	}
+	mls, err := geom.NewMultiLineStringC(coords)
+	if err != nil {
+		panic(err)
+	}
+	return mls
+}
+
+// crossSectionSegment finds the portion of every candidate triangle (found
+// via the R-tree) that the segment p0->p1 passes through, and converts the
+// entry/exit points of each such portion into (distance-along-ls, elevation)
+// profile points, with distances measured from baseDist.
+func (tn *TIN) crossSectionSegment(p0, p1 geom.XY, baseDist float64) [][]geom.Coordinates {
+	segLen := math.Hypot(p1.X-p0.X, p1.Y-p0.Y)
+	box := rtree.Box{
+		MinX: math.Min(p0.X, p1.X), MinY: math.Min(p0.Y, p1.Y),
+		MaxX: math.Max(p0.X, p1.X), MaxY: math.Max(p0.Y, p1.Y),
+	}
+
+	var out [][]geom.Coordinates
+	tn.index.PrioritySearch(box, func(recordID int) error {
+		t := tn.triangles[recordID]
+		if tb := t.box(); tb.MaxX < box.MinX || tb.MinX > box.MaxX ||
+			tb.MaxY < box.MinY || tb.MinY > box.MaxY {
+			// Triangles come in order of distance from box, so none of the
+			// rest can overlap it either.
+			return rtree.Stop
+		}
+		tMin, tMax, ok := clipSegmentToTriangle(p0, p1, t)
+		if !ok {
+			return nil
+		}
+		a := geom.XY{X: p0.X + tMin*(p1.X-p0.X), Y: p0.Y + tMin*(p1.Y-p0.Y)}
+		b := geom.XY{X: p0.X + tMax*(p1.X-p0.X), Y: p0.Y + tMax*(p1.Y-p0.Y)}
+		za, okA := t.ZAt(a.X, a.Y)
+		zb, okB := t.ZAt(b.X, b.Y)
+		if !okA || !okB {
+			return nil
+		}
+		out = append(out, []geom.Coordinates{
+			{XY: geom.XY{X: baseDist + tMin*segLen, Y: za}},
+			{XY: geom.XY{X: baseDist + tMax*segLen, Y: zb}},
+		})
+		return nil
+	})
+	return out
+}
+
+// clipSegmentToTriangle clips the segment p0->p1 against triangle t's planar
+// footprint, using the same successive half-plane clipping technique as
+// clipSegmentToBox in the geom package (generalised from a rectangle's four
+// edges to a triangle's three). It returns the surviving parametric range
+// [tMin, tMax] along the original segment, or false if the segment misses t
+// entirely.
+func clipSegmentToTriangle(p0, p1 geom.XY, t Triangle) (float64, float64, bool) {
+	verts := [3]geom.XY{t.A.XY(), t.B.XY(), t.C.XY()}
+	if signedArea2XY(verts[0], verts[1], verts[2]) < 0 {
+		verts[1], verts[2] = verts[2], verts[1]
+	}
+
+	dx, dy := p1.X-p0.X, p1.Y-p0.Y
+	tMin, tMax := 0.0, 1.0
+	for i := 0; i < 3; i++ {
+		a, b := verts[i], verts[(i+1)%3]
+		nx, ny := a.Y-b.Y, b.X-a.X // inward normal of edge a->b (CCW winding)
+		p := -(dx*nx + dy*ny)
+		q := (p0.X-a.X)*nx + (p0.Y-a.Y)*ny
+		switch {
+		case p == 0:
+			if q < 0 {
+				return 0, 0, false
+			}
+		case p < 0:
+			if tEdge := q / p; tEdge > tMax {
+				return 0, 0, false
+			} else if tEdge > tMin {
+				tMin = tEdge
+			}
+		default:
+			if tEdge := q / p; tEdge < tMin {
+				return 0, 0, false
+			} else if tEdge < tMax {
+				tMax = tEdge
+			}
+		}
+	}
+	if tMin > tMax {
+		return 0, 0, false
+	}
+	return tMin, tMax, true
+}
+
+func signedArea2XY(a, b, c geom.XY) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (c.X-a.X)*(b.Y-a.Y)
+}
@@ -0,0 +1,115 @@
+// Package mesh provides a 3D triangulated-irregular-network (TIN)
+// representation for surface data such as bathymetry or digital elevation
+// models. It is layered on top of the geom package (for its 2D geometry
+// types) and the rtree package (for spatial indexing of triangles), rather
+// than being part of the geom package itself.
+package mesh
+
+import (
+	"math"
+
+	"github.com/peterstace/simplefeatures/geom"
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+// Point3 is a point in 3-dimensional space: a planar (X, Y) position
+// together with an elevation (Z).
+type Point3 struct {
+	X, Y, Z float64
+}
+
+// XY returns the planar projection of p, discarding its Z value.
+func (p Point3) XY() geom.XY {
+	return geom.XY{X: p.X, Y: p.Y}
+}
+
+// Triangle is a single triangular facet of a TIN, given by its three
+// vertices.
+type Triangle struct {
+	A, B, C Point3
+}
+
+// box gives the 2D bounding box of t's planar projection, used to index t in
+// a TIN's spatial index.
+func (t Triangle) box() rtree.Box {
+	return rtree.Box{
+		MinX: math.Min(t.A.X, math.Min(t.B.X, t.C.X)),
+		MinY: math.Min(t.A.Y, math.Min(t.B.Y, t.C.Y)),
+		MaxX: math.Max(t.A.X, math.Max(t.B.X, t.C.X)),
+		MaxY: math.Max(t.A.Y, math.Max(t.B.Y, t.C.Y)),
+	}
+}
+
+// ZAt linearly interpolates t's surface (via barycentric coordinates) to
+// give the elevation at the planar position (x, y). The second return value
+// is false if (x, y) falls outside t (including the degenerate case where t
+// has zero area).
+func (t Triangle) ZAt(x, y float64) (float64, bool) {
+	d := (t.B.Y-t.C.Y)*(t.A.X-t.C.X) + (t.C.X-t.B.X)*(t.A.Y-t.C.Y)
+	if d == 0 {
+		return 0, false
+	}
+	w1 := ((t.B.Y-t.C.Y)*(x-t.C.X) + (t.C.X-t.B.X)*(y-t.C.Y)) / d
+	w2 := ((t.C.Y-t.A.Y)*(x-t.C.X) + (t.A.X-t.C.X)*(y-t.C.Y)) / d
+	w3 := 1 - w1 - w2
+
+	const epsilon = -1e-9
+	if w1 < epsilon || w2 < epsilon || w3 < epsilon {
+		return 0, false
+	}
+	return w1*t.A.Z + w2*t.B.Z + w3*t.C.Z, true
+}
+
+// TIN is a triangulated irregular network: a set of triangular facets whose
+// planar projections partition a region of the XY plane without overlap,
+// each carrying elevation (Z) data.
+type TIN struct {
+	triangles []Triangle
+	index     *rtree.RTree
+}
+
+// NewTIN builds a TIN from a set of triangles, indexing their planar
+// bounding boxes with an STR-packed R-tree (via rtree.BulkLoad) so that
+// queries like ZAt and CrossSection don't have to scan every triangle.
+func NewTIN(triangles []Triangle) *TIN {
+	items := make([]rtree.BulkItem, len(triangles))
+	for i, t := range triangles {
+		items[i] = rtree.BulkItem{Box: t.box(), RecordID: i}
+	}
+	return &TIN{triangles: triangles, index: rtree.BulkLoad(items)}
+}
+
+// Triangles returns the triangles making up the TIN.
+func (tn *TIN) Triangles() []Triangle {
+	return tn.triangles
+}
+
+// ZAt returns the elevation of the TIN's surface at the planar position (x,
+// y), and false if (x, y) falls outside the TIN's footprint.
+func (tn *TIN) ZAt(x, y float64) (float64, bool) {
+	pt := rtree.Box{MinX: x, MinY: y, MaxX: x, MaxY: y}
+	var z float64
+	var found bool
+	tn.index.PrioritySearch(pt, func(recordID int) error {
+		if zz, ok := tn.triangles[recordID].ZAt(x, y); ok {
+			z, found = zz, true
+			return rtree.Stop
+		}
+		return nil
+	})
+	return z, found
+}
+
+// ConvexHull returns the 2D convex hull of the TIN's vertices (ignoring
+// elevation), as a simplefeatures Geometry.
+func (tn *TIN) ConvexHull() geom.Geometry {
+	coords := make([]geom.Coordinates, 0, 3*len(tn.triangles))
+	for _, t := range tn.triangles {
+		coords = append(coords,
+			geom.Coordinates{XY: t.A.XY()},
+			geom.Coordinates{XY: t.B.XY()},
+			geom.Coordinates{XY: t.C.XY()},
+		)
+	}
+	return geom.NewMultiPointC(coords).ConvexHull()
+}
@@ -0,0 +1,118 @@
+package mesh_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/peterstace/simplefeatures/geom/mesh"
+)
+
+func expectFloatEq(t *testing.T, got, want float64) {
+	t.Helper()
+	const epsilon = 1e-9
+	if math.Abs(got-want) > epsilon {
+		t.Errorf("got: %v want: %v", got, want)
+	}
+}
+
+// tiltedTriangle is a single triangle covering the unit square's lower-left
+// half, tilted so that Z rises linearly with X: Z == X everywhere on its
+// surface.
+func tiltedTriangle() mesh.Triangle {
+	return mesh.Triangle{
+		A: mesh.Point3{X: 0, Y: 0, Z: 0},
+		B: mesh.Point3{X: 1, Y: 0, Z: 1},
+		C: mesh.Point3{X: 0, Y: 1, Z: 0},
+	}
+}
+
+func TestTriangleZAt(t *testing.T) {
+	tri := tiltedTriangle()
+
+	for _, tt := range []struct {
+		name   string
+		x, y   float64
+		wantZ  float64
+		wantOK bool
+	}{
+		{name: "vertex A", x: 0, y: 0, wantZ: 0, wantOK: true},
+		{name: "vertex B", x: 1, y: 0, wantZ: 1, wantOK: true},
+		{name: "centroid", x: 1.0 / 3, y: 1.0 / 3, wantZ: 1.0 / 3, wantOK: true},
+		{name: "outside the triangle", x: 1, y: 1, wantOK: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			z, ok := tri.ZAt(tt.x, tt.y)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok=%v, want %v", ok, tt.wantOK)
+			}
+			if ok {
+				expectFloatEq(t, z, tt.wantZ)
+			}
+		})
+	}
+}
+
+func TestTINZAt(t *testing.T) {
+	tn := mesh.NewTIN([]mesh.Triangle{tiltedTriangle()})
+
+	z, ok := tn.ZAt(0.5, 0.25)
+	if !ok {
+		t.Fatalf("ZAt reported not found for a point inside the TIN's footprint")
+	}
+	expectFloatEq(t, z, 0.5)
+
+	if _, ok := tn.ZAt(5, 5); ok {
+		t.Errorf("ZAt reported found for a point well outside the TIN's footprint")
+	}
+}
+
+func TestTINConvexHull(t *testing.T) {
+	tn := mesh.NewTIN([]mesh.Triangle{tiltedTriangle()})
+	hull := tn.ConvexHull()
+	// The triangle's own three vertices are already its convex hull, so the
+	// hull should come back as a triangular polygon (a ring of 4 points:
+	// the 3 vertices plus the closing point).
+	coords := hull.AsPolygon().ExteriorRing().Coordinates()
+	if n := coords.Length(); n != 4 {
+		t.Errorf("got %d points in the hull ring, want 4", n)
+	}
+}
+
+func TestTriangulate(t *testing.T) {
+	points := []mesh.Point3{
+		{X: 0, Y: 0, Z: 0},
+		{X: 10, Y: 0, Z: 0},
+		{X: 10, Y: 10, Z: 0},
+		{X: 0, Y: 10, Z: 0},
+		{X: 5, Y: 5, Z: 1},
+	}
+	tn, err := mesh.Triangulate(nil, points, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tn.Triangles()) == 0 {
+		t.Fatalf("Triangulate produced no triangles")
+	}
+
+	// Every input point should show up as a vertex of at least one
+	// triangle.
+	for _, p := range points {
+		found := false
+		for _, tri := range tn.Triangles() {
+			if tri.A == p || tri.B == p || tri.C == p {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("point %+v doesn't appear in any triangle", p)
+		}
+	}
+}
+
+func TestTriangulateTooFewPoints(t *testing.T) {
+	_, err := mesh.Triangulate(nil, []mesh.Point3{{X: 0, Y: 0}, {X: 1, Y: 1}}, nil)
+	if err == nil {
+		t.Fatalf("expected an error triangulating fewer than 3 points")
+	}
+}
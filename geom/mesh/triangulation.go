@@ -0,0 +1,230 @@
+package mesh
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+// Triangulate builds a constrained Delaunay TIN over points, clipped to the
+// footprint of boundary (a Polygon or MultiPolygon; pass nil to skip
+// clipping), with constraints threaded in as breaklines that the
+// triangulation's vertex set honours.
+//
+// Triangulation proceeds via incremental Bowyer-Watson insertion of every
+// point (plus every vertex of every constraint, so that breaklines are at
+// least represented in the vertex set) into a Delaunay triangulation seeded
+// by a super-triangle enclosing all of them. The resulting triangles are
+// then filtered down to those whose centroid falls within boundary.
+//
+// Note that this does not perform true constrained-edge recovery (flipping
+// triangles so that a breakline's segments are reproduced exactly as
+// triangle edges); it relies on breaklines being sampled densely enough,
+// relative to the surrounding point density, that the unconstrained Delaunay
+// triangulation already tracks them closely.
+func Triangulate(boundary geom.Geometry, points []Point3, constraints []geom.LineString) (*TIN, error) {
+	if len(points) < 3 {
+		return nil, fmt.Errorf("mesh: at least 3 points are required to triangulate, got %d", len(points))
+	}
+
+	verts := append([]Point3(nil), points...)
+	for _, ls := range constraints {
+		seq := ls.Coordinates()
+		for i := 0; i < seq.Length(); i++ {
+			xy := seq.GetXY(i)
+			verts = append(verts, Point3{X: xy.X, Y: xy.Y, Z: nearestZ(points, xy)})
+		}
+	}
+
+	rawTris := bowyerWatson(verts)
+
+	var triangles []Triangle
+	for _, rt := range rawTris {
+		a, b, c := verts[rt.ia], verts[rt.ib], verts[rt.ic]
+		centroid := geom.XY{X: (a.X + b.X + c.X) / 3, Y: (a.Y + b.Y + c.Y) / 3}
+		if boundary != nil && !polygonalContainsXY(boundary, centroid) {
+			continue
+		}
+		triangles = append(triangles, Triangle{A: a, B: b, C: c})
+	}
+	return NewTIN(triangles), nil
+}
+
+// nearestZ approximates the elevation of a constraint vertex that wasn't
+// supplied with its own Z value, by copying the Z value of the closest point
+// in points.
+func nearestZ(points []Point3, xy geom.XY) float64 {
+	best := points[0]
+	bestDistSq := math.Inf(+1)
+	for _, p := range points {
+		dx, dy := p.X-xy.X, p.Y-xy.Y
+		if distSq := dx*dx + dy*dy; distSq < bestDistSq {
+			bestDistSq = distSq
+			best = p
+		}
+	}
+	return best.Z
+}
+
+// delaunayTriangle references its three vertices by index into the vertex
+// slice being triangulated.
+type delaunayTriangle struct {
+	ia, ib, ic int
+}
+
+// bowyerWatson computes the (unconstrained) Delaunay triangulation of verts
+// using the incremental Bowyer-Watson algorithm.
+func bowyerWatson(verts []Point3) []delaunayTriangle {
+	minX, minY := verts[0].X, verts[0].Y
+	maxX, maxY := verts[0].X, verts[0].Y
+	for _, v := range verts[1:] {
+		minX = math.Min(minX, v.X)
+		minY = math.Min(minY, v.Y)
+		maxX = math.Max(maxX, v.X)
+		maxY = math.Max(maxY, v.Y)
+	}
+	delta := math.Max(maxX-minX, maxY-minY)*10 + 1
+	midX, midY := (minX+maxX)/2, (minY+maxY)/2
+
+	work := append(append([]Point3(nil), verts...),
+		Point3{X: midX - 2*delta, Y: midY - delta},
+		Point3{X: midX, Y: midY + 2*delta},
+		Point3{X: midX + 2*delta, Y: midY - delta},
+	)
+	superA, superB, superC := len(work)-3, len(work)-2, len(work)-1
+
+	tris := []delaunayTriangle{{superA, superB, superC}}
+	for i := range verts {
+		tris = insertPoint(work, tris, i)
+	}
+
+	out := tris[:0]
+	for _, t := range tris {
+		if isSuperTriangleVertex(t.ia, superA, superB, superC) ||
+			isSuperTriangleVertex(t.ib, superA, superB, superC) ||
+			isSuperTriangleVertex(t.ic, superA, superB, superC) {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+func isSuperTriangleVertex(idx, a, b, c int) bool {
+	return idx == a || idx == b || idx == c
+}
+
+// insertPoint adds the point at work[pIdx] to the Delaunay triangulation
+// tris, removing every triangle whose circumcircle contains the new point
+// (the resulting "cavity") and re-triangulating the cavity by connecting the
+// new point to every edge of the cavity that was only shared by one removed
+// triangle.
+func insertPoint(work []Point3, tris []delaunayTriangle, pIdx int) []delaunayTriangle {
+	p := work[pIdx]
+
+	bad := make(map[delaunayTriangle]bool)
+	for _, t := range tris {
+		if inCircumcircle(work[t.ia], work[t.ib], work[t.ic], p) {
+			bad[t] = true
+		}
+	}
+
+	type edge struct{ u, v int }
+	edgeCount := make(map[edge]int)
+	addEdge := func(u, v int) {
+		if u > v {
+			u, v = v, u
+		}
+		edgeCount[edge{u, v}]++
+	}
+	for t := range bad {
+		addEdge(t.ia, t.ib)
+		addEdge(t.ib, t.ic)
+		addEdge(t.ic, t.ia)
+	}
+
+	kept := make([]delaunayTriangle, 0, len(tris))
+	for _, t := range tris {
+		if !bad[t] {
+			kept = append(kept, t)
+		}
+	}
+	for e, count := range edgeCount {
+		if count == 1 {
+			kept = append(kept, delaunayTriangle{e.u, e.v, pIdx})
+		}
+	}
+	return kept
+}
+
+// inCircumcircle reports whether p lies strictly within the circumcircle of
+// triangle (a, b, c), using the standard determinant test.
+func inCircumcircle(a, b, c, p Point3) bool {
+	ax, ay := a.X-p.X, a.Y-p.Y
+	bx, by := b.X-p.X, b.Y-p.Y
+	cx, cy := c.X-p.X, c.Y-p.Y
+
+	det := (ax*ax+ay*ay)*(bx*cy-cx*by) -
+		(bx*bx+by*by)*(ax*cy-cx*ay) +
+		(cx*cx+cy*cy)*(ax*by-bx*ay)
+
+	if signedArea2(a, b, c) < 0 {
+		return det < 0
+	}
+	return det > 0
+}
+
+// signedArea2 gives twice the signed area of triangle (a, b, c); positive
+// for a counter-clockwise winding, negative for clockwise.
+func signedArea2(a, b, c Point3) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (c.X-a.X)*(b.Y-a.Y)
+}
+
+// polygonalContainsXY reports whether pt lies within the interior of
+// boundary, which must be a Polygon or MultiPolygon.
+func polygonalContainsXY(boundary geom.Geometry, pt geom.XY) bool {
+	switch boundary.Type() {
+	case geom.TypePolygon:
+		return singlePolygonContainsXY(boundary.AsPolygon(), pt)
+	case geom.TypeMultiPolygon:
+		mp := boundary.AsMultiPolygon()
+		for i := 0; i < mp.NumPolygons(); i++ {
+			if singlePolygonContainsXY(mp.PolygonN(i), pt) {
+				return true
+			}
+		}
+		return false
+	default:
+		panic(fmt.Sprintf("mesh: boundary must be a Polygon or MultiPolygon, got %v", boundary.Type()))
+	}
+}
+
+func singlePolygonContainsXY(p geom.Polygon, pt geom.XY) bool {
+	if !ringContainsXY(p.ExteriorRing().Coordinates(), pt) {
+		return false
+	}
+	for i := 0; i < p.NumInteriorRings(); i++ {
+		if ringContainsXY(p.InteriorRingN(i).Coordinates(), pt) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContainsXY performs a ray-casting point-in-polygon test against a
+// single ring.
+func ringContainsXY(seq geom.Sequence, pt geom.XY) bool {
+	inside := false
+	n := seq.Length()
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := seq.GetXY(j), seq.GetXY(i)
+		if (a.Y > pt.Y) != (b.Y > pt.Y) {
+			xIntersect := (b.X-a.X)*(pt.Y-a.Y)/(b.Y-a.Y) + a.X
+			if pt.X < xIntersect {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
@@ -0,0 +1,222 @@
+// Package raster scan-converts simplefeatures geometries onto a raster
+// image, as a lightweight alternative to pulling in a full drawing stack
+// (e.g. to back map-tile or thumbnail generators). It complements the
+// vector-only output that the rest of simplefeatures produces, in the same
+// spirit as the mesh subpackage complements the geometry model with a 3D
+// surface representation.
+package raster
+
+import (
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+// Affine is a 2D affine transform, mapping a geometry's (X, Y) coordinates
+// to pixel (X, Y) coordinates via:
+//
+//	px = A*x + B*y + E
+//	py = C*x + D*y + F
+type Affine struct {
+	A, B, C, D, E, F float64
+}
+
+// IdentityAffine returns the Affine that leaves coordinates unchanged.
+func IdentityAffine() Affine {
+	return Affine{A: 1, D: 1}
+}
+
+// Apply transforms xy from geometry space into pixel space.
+func (m Affine) Apply(xy geom.XY) (float64, float64) {
+	return m.A*xy.X + m.B*xy.Y + m.E, m.C*xy.X + m.D*xy.Y + m.F
+}
+
+// Rasterizer scan-converts Geometry values onto Dst, transforming from
+// geometry space to pixel space via Transform. Polygons and MultiPolygons
+// are filled using an active-edge-table scanline fill that respects the
+// even-odd rule, so interior rings render as holes. LineStrings and
+// MultiLineStrings are stroked one pixel wide using Bresenham's line
+// algorithm.
+type Rasterizer struct {
+	Dst       draw.Image
+	Transform Affine
+	Color     color.Color
+}
+
+// NewRasterizer returns a Rasterizer that draws onto dst using transform to
+// map geometry coordinates to pixel coordinates, in the given color.
+func NewRasterizer(dst draw.Image, transform Affine, col color.Color) *Rasterizer {
+	return &Rasterizer{Dst: dst, Transform: transform, Color: col}
+}
+
+// Rasterize scan-converts g onto dst using transform, filling polygonal
+// parts of g and stroking lineal parts of g in black.
+func Rasterize(g geom.Geometry, transform Affine, dst draw.Image) {
+	NewRasterizer(dst, transform, color.Black).Draw(g)
+}
+
+// Draw scan-converts g onto r.Dst. Points and empty geometries contribute no
+// pixels.
+func (r *Rasterizer) Draw(g geom.Geometry) {
+	switch g.Type() {
+	case geom.TypePolygon:
+		r.fillPolygon(g.AsPolygon())
+	case geom.TypeMultiPolygon:
+		mp := g.AsMultiPolygon()
+		for i := 0; i < mp.NumPolygons(); i++ {
+			r.fillPolygon(mp.PolygonN(i))
+		}
+	case geom.TypeLineString:
+		r.strokeLineString(g.AsLineString())
+	case geom.TypeMultiLineString:
+		mls := g.AsMultiLineString()
+		for i := 0; i < mls.NumLineStrings(); i++ {
+			r.strokeLineString(mls.LineStringN(i))
+		}
+	case geom.TypeGeometryCollection:
+		gc := g.AsGeometryCollection()
+		for i := 0; i < gc.NumGeometries(); i++ {
+			r.Draw(gc.GeometryN(i))
+		}
+	}
+}
+
+// setPixel sets the pixel at (x, y) to r.Color, defaulting to opaque black
+// if r.Color is nil.
+func (r *Rasterizer) setPixel(x, y int) {
+	col := r.Color
+	if col == nil {
+		col = color.Black
+	}
+	r.Dst.Set(x, y, col)
+}
+
+// fillPolygon fills p's interior (exterior ring minus interior rings) using
+// an active-edge-table scanline fill: every ring's edges are transformed
+// into pixel space and collected together, then for each scanline the
+// x-positions where an edge crosses it are sorted and filled in pairs. This
+// naturally implements the even-odd rule, so interior rings render as holes
+// without any special-casing.
+func (r *Rasterizer) fillPolygon(p geom.Polygon) {
+	var edges []scanEdge
+	addRing := func(seq geom.Sequence) {
+		n := seq.Length()
+		for i := 0; i < n-1; i++ {
+			x0, y0 := r.Transform.Apply(seq.GetXY(i))
+			x1, y1 := r.Transform.Apply(seq.GetXY(i + 1))
+			if y0 == y1 {
+				continue // horizontal edges contribute no crossings
+			}
+			if y0 > y1 {
+				x0, y0, x1, y1 = x1, y1, x0, y0
+			}
+			edges = append(edges, scanEdge{
+				yTop: y0, yBot: y1, x: x0,
+				dxdy: (x1 - x0) / (y1 - y0),
+			})
+		}
+	}
+	addRing(p.ExteriorRing().Coordinates())
+	for i := 0; i < p.NumInteriorRings(); i++ {
+		addRing(p.InteriorRingN(i).Coordinates())
+	}
+	if len(edges) == 0 {
+		return
+	}
+
+	minY, maxY := edges[0].yTop, edges[0].yBot
+	for _, e := range edges[1:] {
+		minY = math.Min(minY, e.yTop)
+		maxY = math.Max(maxY, e.yBot)
+	}
+	bounds := r.Dst.Bounds()
+	yStart := int(math.Max(math.Floor(minY), float64(bounds.Min.Y)))
+	yEnd := int(math.Min(math.Ceil(maxY), float64(bounds.Max.Y)))
+
+	var xs []float64
+	for y := yStart; y < yEnd; y++ {
+		scanY := float64(y) + 0.5
+		xs = xs[:0]
+		for _, e := range edges {
+			if scanY < e.yTop || scanY >= e.yBot {
+				continue
+			}
+			xs = append(xs, e.x+(scanY-e.yTop)*e.dxdy)
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			xStart := int(math.Round(xs[i]))
+			xEnd := int(math.Round(xs[i+1]))
+			for x := xStart; x < xEnd; x++ {
+				r.setPixel(x, y)
+			}
+		}
+	}
+}
+
+// scanEdge is one edge of a polygon's rings, transformed into pixel space
+// and oriented so that yTop <= yBot, ready for use in fillPolygon's
+// active-edge-table scan.
+type scanEdge struct {
+	yTop, yBot float64
+	x          float64 // x at yTop
+	dxdy       float64
+}
+
+// strokeLineString draws each segment of ls in pixel space using Bresenham's
+// line algorithm. Bresenham is used rather than Wu's antialiased variant
+// because Dst is typically a 1-bit-per-pixel mask (e.g. image.Alpha) for
+// which antialiasing has no benefit.
+func (r *Rasterizer) strokeLineString(ls geom.LineString) {
+	seq := ls.Coordinates()
+	for i := 0; i < seq.Length()-1; i++ {
+		x0, y0 := r.Transform.Apply(seq.GetXY(i))
+		x1, y1 := r.Transform.Apply(seq.GetXY(i + 1))
+		r.bresenhamLine(x0, y0, x1, y1)
+	}
+}
+
+// bresenhamLine draws the line from (x0, y0) to (x1, y1) (in pixel space)
+// using Bresenham's integer line algorithm.
+func (r *Rasterizer) bresenhamLine(x0, y0, x1, y1 float64) {
+	ix0, iy0 := int(math.Round(x0)), int(math.Round(y0))
+	ix1, iy1 := int(math.Round(x1)), int(math.Round(y1))
+
+	dx := absInt(ix1 - ix0)
+	dy := -absInt(iy1 - iy0)
+	sx, sy := 1, 1
+	if ix0 >= ix1 {
+		sx = -1
+	}
+	if iy0 >= iy1 {
+		sy = -1
+	}
+	err := dx + dy
+
+	x, y := ix0, iy0
+	for {
+		r.setPixel(x, y)
+		if x == ix1 && y == iy1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,107 @@
+package geom
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GeometryFormat selects the encoding a GeometryScanner reads.
+type GeometryFormat int
+
+const (
+	// FormatWKB scans a stream of back-to-back WKB geometries (the
+	// format WKBWriter produces), with no delimiter between them: each
+	// geometry's own length is implied by its contents.
+	FormatWKB GeometryFormat = iota
+
+	// FormatWKT scans a stream of newline-delimited WKT geometries.
+	// Blank lines are skipped.
+	FormatWKT
+)
+
+// GeometryScanner lazily reads one Geometry at a time from a stream, so
+// that large inputs (e.g. a planet-scale OSM extract) can be processed
+// without holding the whole thing in memory. Construct one with
+// NewGeometryScanner and call Scan in a loop, as with bufio.Scanner.
+//
+// Newline-delimited GeoJSON isn't supported: UnmarshalGeoJSON currently
+// returns a GeometryX rather than a Geometry (see geom/limit/load.go for
+// the same gap), and there isn't yet a principled way to bridge the two.
+type GeometryScanner struct {
+	ctx    context.Context
+	format GeometryFormat
+
+	wkbReader *WKBReader
+	wktLines  *bufio.Scanner
+
+	err error
+}
+
+// NewGeometryScanner returns a GeometryScanner that reads geometries
+// encoded as format from r. Each call to Scan checks ctx for
+// cancellation, so a caller can abort a long-running scan by cancelling
+// ctx.
+func NewGeometryScanner(ctx context.Context, r io.Reader, format GeometryFormat) *GeometryScanner {
+	s := &GeometryScanner{ctx: ctx, format: format}
+	switch format {
+	case FormatWKB:
+		s.wkbReader = NewWKBReader(r)
+	case FormatWKT:
+		s.wktLines = bufio.NewScanner(r)
+	}
+	return s
+}
+
+// Scan advances the scanner to the next geometry, returning it along
+// with true. It returns false once the input is exhausted, once ctx is
+// done, or on a parse error -- use Err to distinguish the latter two from
+// ordinary end of input.
+func (s *GeometryScanner) Scan() (Geometry, bool) {
+	if s.err != nil {
+		return nil, false
+	}
+	if err := s.ctx.Err(); err != nil {
+		s.err = err
+		return nil, false
+	}
+
+	switch s.format {
+	case FormatWKB:
+		g, _, err := s.wkbReader.Read()
+		if err != nil {
+			if err != io.EOF {
+				s.err = err
+			}
+			return nil, false
+		}
+		return g, true
+	case FormatWKT:
+		for s.wktLines.Scan() {
+			line := strings.TrimSpace(s.wktLines.Text())
+			if line == "" {
+				continue
+			}
+			g, err := UnmarshalWKT(strings.NewReader(line))
+			if err != nil {
+				s.err = err
+				return nil, false
+			}
+			return g, true
+		}
+		s.err = s.wktLines.Err()
+		return nil, false
+	default:
+		s.err = fmt.Errorf("geom: GeometryScanner: unknown GeometryFormat %d", s.format)
+		return nil, false
+	}
+}
+
+// Err returns the first error encountered by Scan (including ctx's error,
+// if Scan stopped because ctx was done), or nil if the stream was
+// exhausted without one.
+func (s *GeometryScanner) Err() error {
+	return s.err
+}
@@ -1,9 +1,6 @@
 package geom
 
-import (
-	"database/sql/driver"
-	"io"
-)
+//go:generate go run ../internal/gen/main.go
 
 // MultiPoint is a 0-dimensional geometric collection of points. The points are
 // not connected or ordered.
@@ -13,10 +10,16 @@ import (
 // 1. It must be made up of 0 or more valid Points.
 type MultiPoint struct {
 	pts []Point
+
+	// spatialIndex lazily caches the R-tree built by index(). It's a
+	// pointer so that every MultiPoint value copied from the same original
+	// (e.g. via AsMultiPoint) shares the one cache, rather than each copy
+	// rebuilding its own.
+	spatialIndex *rtreeIndex
 }
 
 func NewMultiPoint(pts []Point, opts ...ConstructorOption) MultiPoint {
-	return MultiPoint{pts}
+	return MultiPoint{pts: pts, spatialIndex: new(rtreeIndex)}
 }
 
 // NewMultiPointOC creates a new MultiPoint consisting of a Point for each
@@ -53,25 +56,6 @@ func (m MultiPoint) PointN(n int) Point {
 	return m.pts[n]
 }
 
-func (m MultiPoint) AsText() string {
-	return string(m.AppendWKT(nil))
-}
-
-func (m MultiPoint) AppendWKT(dst []byte) []byte {
-	dst = append(dst, []byte("MULTIPOINT")...)
-	if len(m.pts) == 0 {
-		return append(dst, []byte(" EMPTY")...)
-	}
-	dst = append(dst, '(')
-	for i, pt := range m.pts {
-		dst = pt.appendWKTBody(dst)
-		if i != len(m.pts)-1 {
-			dst = append(dst, ',')
-		}
-	}
-	return append(dst, ')')
-}
-
 // IsSimple returns true iff no two of its points are equal.
 func (m MultiPoint) IsSimple() bool {
 	seen := make(map[XY]bool)
@@ -88,29 +72,6 @@ func (m MultiPoint) Intersection(g Geometry) Geometry {
 	return intersection(m, g)
 }
 
-func (m MultiPoint) IsEmpty() bool {
-	return len(m.pts) == 0
-}
-
-func (m MultiPoint) Dimension() int {
-	return 0
-}
-
-func (m MultiPoint) Equals(other Geometry) bool {
-	return equals(m, other)
-}
-
-func (m MultiPoint) Envelope() (Envelope, bool) {
-	if len(m.pts) == 0 {
-		return Envelope{}, false
-	}
-	env := NewEnvelope(m.pts[0].coords.XY)
-	for _, pt := range m.pts[1:] {
-		env = env.Extend(pt.coords.XY)
-	}
-	return env, true
-}
-
 func (m MultiPoint) Boundary() Geometry {
 	// This is a little bit more complicated than it really has to be (it just
 	// has to always return an empty set). However, this is the behavour of
@@ -121,23 +82,6 @@ func (m MultiPoint) Boundary() Geometry {
 	return NewGeometryCollection(nil)
 }
 
-func (m MultiPoint) Value() (driver.Value, error) {
-	return wkbAsBytes(m)
-}
-
-func (m MultiPoint) AsBinary(w io.Writer) error {
-	marsh := newWKBMarshaller(w)
-	marsh.writeByteOrder()
-	marsh.writeGeomType(wkbGeomTypeMultiPoint)
-	n := m.NumPoints()
-	marsh.writeCount(n)
-	for i := 0; i < n; i++ {
-		pt := m.PointN(i)
-		marsh.setErr(pt.AsBinary(w))
-	}
-	return marsh.err
-}
-
 // ConvexHull finds the convex hull of the set of points. This may either be
 // the empty set, a single point, a line, or a polygon.
 func (m MultiPoint) ConvexHull() Geometry {
@@ -152,24 +96,3 @@ func (m MultiPoint) convexHullPointSet() []XY {
 	}
 	return points
 }
-
-func (m MultiPoint) MarshalJSON() ([]byte, error) {
-	return marshalGeoJSON("MultiPoint", m.Coordinates())
-}
-
-// Coordinates returns the coordinates of the points represented by the
-// MultiPoint.
-func (m MultiPoint) Coordinates() []Coordinates {
-	coords := make([]Coordinates, len(m.pts))
-	for i := range coords {
-		coords[i] = m.pts[i].Coordinates()
-	}
-	return coords
-}
-
-// TransformXY transforms this MultiPoint into another MultiPoint according to fn.
-func (m MultiPoint) TransformXY(fn func(XY) XY, opts ...ConstructorOption) (Geometry, error) {
-	coords := m.Coordinates()
-	transform1dCoords(coords, fn)
-	return NewMultiPointC(coords, opts...), nil
-}
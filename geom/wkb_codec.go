@@ -0,0 +1,754 @@
+package geom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// ParseError is returned by WKBReader when the input is malformed, and by
+// WKBWriter when asked to write something it can't yet encode. Offset is
+// the byte offset (within the current geometry being decoded) at which the
+// problem was found; it's zero for writer-side errors.
+type ParseError struct {
+	Reason string
+	Offset int64
+}
+
+func (e *ParseError) Error() string {
+	if e.Offset == 0 {
+		return fmt.Sprintf("geom: %s", e.Reason)
+	}
+	return fmt.Sprintf("geom: parse error at byte offset %d: %s", e.Offset, e.Reason)
+}
+
+// ByteOrder selects the endianness a WKBWriter encodes with, or that a
+// WKBReader last decoded with. The values match the WKB byte order marker
+// byte (1 for little endian, 0 for big endian).
+type ByteOrder byte
+
+const (
+	LittleEndian ByteOrder = 1
+	BigEndian    ByteOrder = 0
+)
+
+// wkb type codes, per the OGC Simple Features WKB spec.
+const (
+	wkbTypePoint              = 1
+	wkbTypeLineString         = 2
+	wkbTypePolygon            = 3
+	wkbTypeMultiPoint         = 4
+	wkbTypeMultiLineString    = 5
+	wkbTypeMultiPolygon       = 6
+	wkbTypeGeometryCollection = 7
+)
+
+// wkbSRIDFlag is the PostGIS EWKB extension bit (set in the type word)
+// that indicates a SRID immediately follows the type word.
+const wkbSRIDFlag = 0x20000000
+
+// wkbZFlag and wkbMFlag are the PostGIS EWKB extension bits that indicate a
+// geometry carries a Z and/or M value per coordinate, set in the type word
+// alongside wkbSRIDFlag.
+const (
+	wkbZFlag   = 0x80000000
+	wkbMFlag   = 0x40000000
+	wkbZMFlags = wkbZFlag | wkbMFlag
+)
+
+// coordinatesTypeToWKBFlags gives the EWKB type-word flag bits for ct.
+func coordinatesTypeToWKBFlags(ct CoordinatesType) uint32 {
+	var flags uint32
+	if ct.Is3D() {
+		flags |= wkbZFlag
+	}
+	if ct.IsMeasured() {
+		flags |= wkbMFlag
+	}
+	return flags
+}
+
+// wkbFlagsToCoordinatesType is the inverse of coordinatesTypeToWKBFlags,
+// given just the Z/M bits (already masked out of the rest of the type
+// word).
+func wkbFlagsToCoordinatesType(flags uint32) CoordinatesType {
+	switch flags & wkbZMFlags {
+	case wkbZFlag:
+		return XYZ
+	case wkbMFlag:
+		return XYM
+	case wkbZFlag | wkbMFlag:
+		return XYZM
+	default:
+		return XY
+	}
+}
+
+// WKBWriterOption configures a WKBWriter constructed by NewWKBWriter.
+type WKBWriterOption func(*wkbWriterOptions)
+
+type wkbWriterOptions struct {
+	ct          CoordinatesType
+	includeSRID bool
+	srid        int
+	byteOrder   ByteOrder
+	hexOutput   bool
+}
+
+// OutputDimension sets the coordinate dimension a WKBWriter encodes: 2
+// (XY), 3 or 4 (XYZM). It defaults to 2. NewWKBWriter panics if dim isn't
+// one of those three values.
+//
+// Dimension 3 is ambiguous between XYZ and XYM (PostGIS's EWKB Z and M
+// flags are independent bits, not a single dimension count), so
+// OutputDimension(3) selects XYZ, the far more common case. Use
+// OutputCoordinatesType(XYM) instead if the geometry being written carries
+// measures rather than heights.
+func OutputDimension(dim int) WKBWriterOption {
+	var ct CoordinatesType
+	switch dim {
+	case 2:
+		ct = XY
+	case 3:
+		ct = XYZ
+	case 4:
+		ct = XYZM
+	default:
+		ct = CoordinatesType(-1) // caught by the dimension check in NewWKBWriter
+	}
+	return func(o *wkbWriterOptions) { o.ct = ct }
+}
+
+// OutputCoordinatesType sets the exact CoordinatesType a WKBWriter encodes
+// with, disambiguating the XYZ/XYM case that OutputDimension(3) can't.
+func OutputCoordinatesType(ct CoordinatesType) WKBWriterOption {
+	return func(o *wkbWriterOptions) { o.ct = ct }
+}
+
+// IncludeSRID switches a WKBWriter to PostGIS-style EWKB output, setting
+// the SRID flag bit in the type word and prefixing the geometry body with
+// srid.
+func IncludeSRID(srid int) WKBWriterOption {
+	return func(o *wkbWriterOptions) {
+		o.includeSRID = true
+		o.srid = srid
+	}
+}
+
+// WKBByteOrder sets the byte order a WKBWriter encodes with. It defaults
+// to LittleEndian.
+func WKBByteOrder(order ByteOrder) WKBWriterOption {
+	return func(o *wkbWriterOptions) { o.byteOrder = order }
+}
+
+// HexOutput switches a WKBWriter to emit upper-case hex-encoded bytes (as
+// used by e.g. PostGIS's ST_AsEWKB text representation) rather than raw
+// binary.
+func HexOutput(enabled bool) WKBWriterOption {
+	return func(o *wkbWriterOptions) { o.hexOutput = enabled }
+}
+
+// WKBWriter writes geometries as (optionally extended) WKB. Construct one
+// with NewWKBWriter and reuse it to write multiple geometries with the
+// same options.
+type WKBWriter struct {
+	w    io.Writer
+	opts wkbWriterOptions
+}
+
+// NewWKBWriter returns a WKBWriter that writes to w, configured by opts.
+// It panics if OutputDimension is given a value outside {2, 3, 4}.
+func NewWKBWriter(w io.Writer, opts ...WKBWriterOption) *WKBWriter {
+	o := wkbWriterOptions{ct: XY, byteOrder: LittleEndian}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.ct != XY && o.ct != XYZ && o.ct != XYM && o.ct != XYZM {
+		panic("geom: WKBWriter: OutputDimension must be 2, 3 or 4")
+	}
+	return &WKBWriter{w: w, opts: o}
+}
+
+// Write encodes g and writes it to the writer wr was constructed with.
+func (wr *WKBWriter) Write(g Geometry) error {
+	var buf bytes.Buffer
+	enc := &wkbEncoder{w: &buf, order: wr.opts.byteOrder, ct: wr.opts.ct}
+	if err := enc.encodeGeometry(g, wr.opts.includeSRID, wr.opts.srid); err != nil {
+		return err
+	}
+
+	if wr.opts.hexOutput {
+		_, err := io.WriteString(wr.w, strings.ToUpper(hex.EncodeToString(buf.Bytes())))
+		return err
+	}
+	_, err := wr.w.Write(buf.Bytes())
+	return err
+}
+
+type wkbEncoder struct {
+	w     io.Writer
+	order ByteOrder
+	ct    CoordinatesType
+}
+
+func (e *wkbEncoder) writeByte(b byte) error {
+	_, err := e.w.Write([]byte{b})
+	return err
+}
+
+func (e *wkbEncoder) writeUint32(v uint32) error {
+	var buf [4]byte
+	if e.order == LittleEndian {
+		binary.LittleEndian.PutUint32(buf[:], v)
+	} else {
+		binary.BigEndian.PutUint32(buf[:], v)
+	}
+	_, err := e.w.Write(buf[:])
+	return err
+}
+
+func (e *wkbEncoder) writeFloat64(v float64) error {
+	bits := math.Float64bits(v)
+	var buf [8]byte
+	if e.order == LittleEndian {
+		binary.LittleEndian.PutUint64(buf[:], bits)
+	} else {
+		binary.BigEndian.PutUint64(buf[:], bits)
+	}
+	_, err := e.w.Write(buf[:])
+	return err
+}
+
+func (e *wkbEncoder) writeXY(xy XY) error {
+	if err := e.writeFloat64(xy.X); err != nil {
+		return err
+	}
+	return e.writeFloat64(xy.Y)
+}
+
+// writeCoordinates writes c's X and Y, followed by its Z and/or M values if
+// e.ct calls for them.
+func (e *wkbEncoder) writeCoordinates(c Coordinates) error {
+	if err := e.writeXY(c.XY); err != nil {
+		return err
+	}
+	if e.ct.Is3D() {
+		if err := e.writeFloat64(c.Z); err != nil {
+			return err
+		}
+	}
+	if e.ct.IsMeasured() {
+		if err := e.writeFloat64(c.M); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *wkbEncoder) writeHeader(typeCode uint32, includeSRID bool, srid int) error {
+	typeCode |= coordinatesTypeToWKBFlags(e.ct)
+	if includeSRID {
+		typeCode |= wkbSRIDFlag
+	}
+	if err := e.writeByte(byte(e.order)); err != nil {
+		return err
+	}
+	if err := e.writeUint32(typeCode); err != nil {
+		return err
+	}
+	if includeSRID {
+		return e.writeUint32(uint32(srid))
+	}
+	return nil
+}
+
+func (e *wkbEncoder) writeSequence(seq Sequence) error {
+	n := seq.Length()
+	if err := e.writeUint32(uint32(n)); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := e.writeCoordinates(seq.GetCoordinates(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *wkbEncoder) encodeGeometry(g Geometry, includeSRID bool, srid int) error {
+	switch g.Type() {
+	case TypePoint:
+		return e.encodePoint(g.AsPoint(), includeSRID, srid)
+	case TypeLineString:
+		return e.encodeLineString(g.AsLineString(), includeSRID, srid)
+	case TypePolygon:
+		return e.encodePolygon(g.AsPolygon(), includeSRID, srid)
+	case TypeMultiPoint:
+		return e.encodeMultiPoint(g.AsMultiPoint(), includeSRID, srid)
+	case TypeMultiLineString:
+		return e.encodeMultiLineString(g.AsMultiLineString(), includeSRID, srid)
+	case TypeMultiPolygon:
+		return e.encodeMultiPolygon(g.AsMultiPolygon(), includeSRID, srid)
+	case TypeGeometryCollection:
+		return e.encodeGeometryCollection(g.AsGeometryCollection(), includeSRID, srid)
+	default:
+		return &ParseError{Reason: fmt.Sprintf("WKBWriter: unsupported geometry type %v", g.Type())}
+	}
+}
+
+func (e *wkbEncoder) encodePoint(pt Point, includeSRID bool, srid int) error {
+	if err := e.writeHeader(wkbTypePoint, includeSRID, srid); err != nil {
+		return err
+	}
+	if pt.IsEmpty() {
+		return e.writeCoordinates(Coordinates{
+			XY: XY{X: math.NaN(), Y: math.NaN()},
+			Z:  math.NaN(),
+			M:  math.NaN(),
+		})
+	}
+	return e.writeCoordinates(pt.Coordinates())
+}
+
+func (e *wkbEncoder) encodeLineString(ls LineString, includeSRID bool, srid int) error {
+	if err := e.writeHeader(wkbTypeLineString, includeSRID, srid); err != nil {
+		return err
+	}
+	return e.writeSequence(ls.Coordinates())
+}
+
+func (e *wkbEncoder) encodePolygon(p Polygon, includeSRID bool, srid int) error {
+	if err := e.writeHeader(wkbTypePolygon, includeSRID, srid); err != nil {
+		return err
+	}
+	if p.IsEmpty() {
+		return e.writeUint32(0)
+	}
+	n := p.NumInteriorRings()
+	if err := e.writeUint32(uint32(1 + n)); err != nil {
+		return err
+	}
+	if err := e.writeSequence(p.ExteriorRing().Coordinates()); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := e.writeSequence(p.InteriorRingN(i).Coordinates()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *wkbEncoder) encodeMultiPoint(mp MultiPoint, includeSRID bool, srid int) error {
+	if err := e.writeHeader(wkbTypeMultiPoint, includeSRID, srid); err != nil {
+		return err
+	}
+	n := mp.NumPoints()
+	if err := e.writeUint32(uint32(n)); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := e.encodePoint(mp.PointN(i), false, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *wkbEncoder) encodeMultiLineString(mls MultiLineString, includeSRID bool, srid int) error {
+	if err := e.writeHeader(wkbTypeMultiLineString, includeSRID, srid); err != nil {
+		return err
+	}
+	n := mls.NumLineStrings()
+	if err := e.writeUint32(uint32(n)); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := e.encodeLineString(mls.LineStringN(i), false, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *wkbEncoder) encodeMultiPolygon(mp MultiPolygon, includeSRID bool, srid int) error {
+	if err := e.writeHeader(wkbTypeMultiPolygon, includeSRID, srid); err != nil {
+		return err
+	}
+	n := mp.NumPolygons()
+	if err := e.writeUint32(uint32(n)); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := e.encodePolygon(mp.PolygonN(i), false, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *wkbEncoder) encodeGeometryCollection(gc GeometryCollection, includeSRID bool, srid int) error {
+	if err := e.writeHeader(wkbTypeGeometryCollection, includeSRID, srid); err != nil {
+		return err
+	}
+	n := gc.NumGeometries()
+	if err := e.writeUint32(uint32(n)); err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := e.encodeGeometry(gc.GeometryN(i), false, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WKBReaderOption configures a WKBReader constructed by NewWKBReader.
+type WKBReaderOption func(*wkbReaderOptions)
+
+type wkbReaderOptions struct {
+	hexInput bool
+}
+
+// HexInput configures a WKBReader to expect hex-encoded input (as
+// produced by HexOutput, or by PostGIS's ST_AsEWKB text representation)
+// rather than raw binary.
+func HexInput(enabled bool) WKBReaderOption {
+	return func(o *wkbReaderOptions) { o.hexInput = enabled }
+}
+
+// WKBReader reads (optionally extended) WKB geometries. Construct one
+// with NewWKBReader and reuse it to read multiple geometries from the
+// same stream.
+type WKBReader struct {
+	r    io.Reader
+	opts wkbReaderOptions
+
+	// br buffers r across calls to Read, so that bytes read ahead of one
+	// geometry are still there for the next.
+	br *bufio.Reader
+}
+
+// NewWKBReader returns a WKBReader that reads from r, configured by opts.
+func NewWKBReader(r io.Reader, opts ...WKBReaderOption) *WKBReader {
+	o := wkbReaderOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &WKBReader{r: r, opts: o}
+}
+
+// Read parses and returns the next geometry from the stream, along with
+// its SRID (0 if the input wasn't EWKB, or didn't set a SRID). Read
+// returns io.EOF (unwrapped, so callers can compare with ==) if the
+// stream ends cleanly between geometries.
+func (rd *WKBReader) Read() (Geometry, int, error) {
+	if rd.br == nil {
+		r := rd.r
+		if rd.opts.hexInput {
+			r = hex.NewDecoder(r)
+		}
+		rd.br = bufio.NewReader(r)
+	}
+	dec := &wkbDecoder{r: rd.br}
+	return dec.decodeGeometry()
+}
+
+type wkbDecoder struct {
+	r     *bufio.Reader
+	order ByteOrder
+	n     int64
+}
+
+func (d *wkbDecoder) fail(reason string) error {
+	return &ParseError{Reason: reason, Offset: d.n}
+}
+
+func (d *wkbDecoder) readByte() (byte, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return 0, d.fail("unexpected end of input")
+	}
+	d.n++
+	return b, nil
+}
+
+func (d *wkbDecoder) readUint32() (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(d.r, buf[:]); err != nil {
+		return 0, d.fail("unexpected end of input reading a uint32")
+	}
+	d.n += 4
+	if d.order == LittleEndian {
+		return binary.LittleEndian.Uint32(buf[:]), nil
+	}
+	return binary.BigEndian.Uint32(buf[:]), nil
+}
+
+func (d *wkbDecoder) readFloat64() (float64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(d.r, buf[:]); err != nil {
+		return 0, d.fail("unexpected end of input reading a float64")
+	}
+	d.n += 8
+	var bits uint64
+	if d.order == LittleEndian {
+		bits = binary.LittleEndian.Uint64(buf[:])
+	} else {
+		bits = binary.BigEndian.Uint64(buf[:])
+	}
+	return math.Float64frombits(bits), nil
+}
+
+func (d *wkbDecoder) readXY() (XY, error) {
+	x, err := d.readFloat64()
+	if err != nil {
+		return XY{}, err
+	}
+	y, err := d.readFloat64()
+	if err != nil {
+		return XY{}, err
+	}
+	return XY{X: x, Y: y}, nil
+}
+
+// readCoordinates reads a single coordinate tuple, including a Z and/or M
+// value if ct calls for them.
+func (d *wkbDecoder) readCoordinates(ct CoordinatesType) (Coordinates, error) {
+	xy, err := d.readXY()
+	if err != nil {
+		return Coordinates{}, err
+	}
+	c := Coordinates{XY: xy, Type: ct}
+	if ct.Is3D() {
+		if c.Z, err = d.readFloat64(); err != nil {
+			return Coordinates{}, err
+		}
+	}
+	if ct.IsMeasured() {
+		if c.M, err = d.readFloat64(); err != nil {
+			return Coordinates{}, err
+		}
+	}
+	return c, nil
+}
+
+func (d *wkbDecoder) readCoordinatesSlice(ct CoordinatesType) ([]Coordinates, error) {
+	n, err := d.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	coords := make([]Coordinates, n)
+	for i := range coords {
+		c, err := d.readCoordinates(ct)
+		if err != nil {
+			return nil, err
+		}
+		coords[i] = c
+	}
+	return coords, nil
+}
+
+func (d *wkbDecoder) readRings(ct CoordinatesType) ([][]Coordinates, error) {
+	n, err := d.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	rings := make([][]Coordinates, n)
+	for i := range rings {
+		ring, err := d.readCoordinatesSlice(ct)
+		if err != nil {
+			return nil, err
+		}
+		rings[i] = ring
+	}
+	return rings, nil
+}
+
+// readHeader reads a nested geometry's byte order marker and type word
+// (without the top-level SRID handling decodeGeometry does), and errors
+// unless the base type matches want. It returns the CoordinatesType implied
+// by the nested type word's Z/M flags, which the caller is expected to
+// already know matches the enclosing multi-geometry's own CoordinatesType.
+func (d *wkbDecoder) readHeader(want uint32) (CoordinatesType, error) {
+	orderByte, err := d.readByte()
+	if err != nil {
+		return XY, err
+	}
+	switch orderByte {
+	case byte(LittleEndian):
+		d.order = LittleEndian
+	case byte(BigEndian):
+		d.order = BigEndian
+	default:
+		return XY, d.fail(fmt.Sprintf("invalid byte order marker: %#x", orderByte))
+	}
+	tc, err := d.readUint32()
+	if err != nil {
+		return XY, err
+	}
+	ct := wkbFlagsToCoordinatesType(tc)
+	tc &^= wkbZMFlags
+	if tc != want {
+		return XY, d.fail(fmt.Sprintf("expected nested geometry type %d, got %d", want, tc))
+	}
+	return ct, nil
+}
+
+// decodeGeometry reads a full geometry (byte order marker, type word -
+// including EWKB SRID/Z/M flags - and body) from the front of the stream.
+// It returns io.EOF (unwrapped) if the stream is cleanly exhausted right
+// at the start of a geometry, rather than mid-way through one.
+func (d *wkbDecoder) decodeGeometry() (Geometry, int, error) {
+	orderByte, err := d.r.ReadByte()
+	if err == io.EOF {
+		return nil, 0, io.EOF
+	}
+	if err != nil {
+		return nil, 0, d.fail("unexpected end of input")
+	}
+	d.n++
+	switch orderByte {
+	case byte(LittleEndian):
+		d.order = LittleEndian
+	case byte(BigEndian):
+		d.order = BigEndian
+	default:
+		return nil, 0, d.fail(fmt.Sprintf("invalid byte order marker: %#x", orderByte))
+	}
+
+	typeCode, err := d.readUint32()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	srid := 0
+	if typeCode&wkbSRIDFlag != 0 {
+		typeCode &^= wkbSRIDFlag
+		s, err := d.readUint32()
+		if err != nil {
+			return nil, 0, err
+		}
+		srid = int(s)
+	}
+	ct := wkbFlagsToCoordinatesType(typeCode)
+	typeCode &^= wkbZMFlags
+
+	g, err := d.decodeBody(typeCode, ct)
+	return g, srid, err
+}
+
+func (d *wkbDecoder) decodeBody(typeCode uint32, ct CoordinatesType) (Geometry, error) {
+	switch typeCode {
+	case wkbTypePoint:
+		c, err := d.readCoordinates(ct)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(c.XY.X) && math.IsNaN(c.XY.Y) {
+			// encodePoint writes an empty Point as NaN coordinates.
+			return NewEmptyPoint(), nil
+		}
+		return NewPointC(c), nil
+	case wkbTypeLineString:
+		coords, err := d.readCoordinatesSlice(ct)
+		if err != nil {
+			return nil, err
+		}
+		ls, err := NewLineStringC(coords)
+		if err != nil {
+			return nil, &ParseError{Reason: err.Error(), Offset: d.n}
+		}
+		return ls, nil
+	case wkbTypePolygon:
+		rings, err := d.readRings(ct)
+		if err != nil {
+			return nil, err
+		}
+		p, err := NewPolygonC(rings)
+		if err != nil {
+			return nil, &ParseError{Reason: err.Error(), Offset: d.n}
+		}
+		return p, nil
+	case wkbTypeMultiPoint:
+		n, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		coords := make([]Coordinates, n)
+		for i := range coords {
+			if _, err := d.readHeader(wkbTypePoint); err != nil {
+				return nil, err
+			}
+			c, err := d.readCoordinates(ct)
+			if err != nil {
+				return nil, err
+			}
+			coords[i] = c
+		}
+		return NewMultiPointC(coords), nil
+	case wkbTypeMultiLineString:
+		n, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		lines := make([][]Coordinates, n)
+		for i := range lines {
+			if _, err := d.readHeader(wkbTypeLineString); err != nil {
+				return nil, err
+			}
+			coords, err := d.readCoordinatesSlice(ct)
+			if err != nil {
+				return nil, err
+			}
+			lines[i] = coords
+		}
+		mls, err := NewMultiLineStringC(lines)
+		if err != nil {
+			return nil, &ParseError{Reason: err.Error(), Offset: d.n}
+		}
+		return mls, nil
+	case wkbTypeMultiPolygon:
+		n, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		polys := make([][][]Coordinates, n)
+		for i := range polys {
+			if _, err := d.readHeader(wkbTypePolygon); err != nil {
+				return nil, err
+			}
+			rings, err := d.readRings(ct)
+			if err != nil {
+				return nil, err
+			}
+			polys[i] = rings
+		}
+		mp, err := NewMultiPolygonC(polys)
+		if err != nil {
+			return nil, &ParseError{Reason: err.Error(), Offset: d.n}
+		}
+		return mp, nil
+	case wkbTypeGeometryCollection:
+		n, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+		geoms := make([]Geometry, n)
+		for i := range geoms {
+			g, _, err := d.decodeGeometry()
+			if err != nil {
+				return nil, err
+			}
+			geoms[i] = g
+		}
+		return NewGeometryCollection(geoms), nil
+	default:
+		return nil, d.fail(fmt.Sprintf("unsupported WKB geometry type code: %d", typeCode))
+	}
+}
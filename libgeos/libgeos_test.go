@@ -41,6 +41,23 @@ func TestRelease(t *testing.T) {
 // testing is just enough to make use confident that we're invoking libgeos
 // correctly.
 
+// expectNativeMatchesGEOS checks that name, a native geom package DE-9IM
+// predicate, agrees with want (GEOS's answer for the same g1/g2, via this
+// package's CGO wrapper) on the same WKT fixture TestRelate already uses.
+// This is what gives geom.Relate's native predicates GEOS-equivalence
+// coverage, rather than relying solely on the much smaller hand-written
+// table in geom/alg_relate_test.go.
+func expectNativeMatchesGEOS(t *testing.T, name string, native func(g1, g2 geom.Geometry) (bool, error), g1, g2 geom.Geometry, wkt1, wkt2 string, want bool) {
+	t.Helper()
+	got, err := native(g1, g2)
+	expectNoErr(t, err)
+	if got != want {
+		t.Logf("WKT1: %v", wkt1)
+		t.Logf("WKT2: %v", wkt2)
+		t.Errorf("native geom.%s disagrees with GEOS: got: %v want: %v", name, got, want)
+	}
+}
+
 func TestRelate(t *testing.T) {
 	for i, tt := range []struct {
 		wkt1, wkt2 string
@@ -405,6 +422,30 @@ func TestRelate(t *testing.T) {
 					t.Errorf("got: %v want: %v", got, tt.coveredBy)
 				}
 			})
+			t.Run("NativeEquals", func(t *testing.T) {
+				expectNativeMatchesGEOS(t, "Equals", geom.Equals, g1, g2, tt.wkt1, tt.wkt2, tt.equals)
+			})
+			t.Run("NativeDisjoint", func(t *testing.T) {
+				expectNativeMatchesGEOS(t, "Disjoint", geom.Disjoint, g1, g2, tt.wkt1, tt.wkt2, tt.disjoint)
+			})
+			t.Run("NativeTouches", func(t *testing.T) {
+				expectNativeMatchesGEOS(t, "Touches", geom.Touches, g1, g2, tt.wkt1, tt.wkt2, tt.touches)
+			})
+			t.Run("NativeContains", func(t *testing.T) {
+				expectNativeMatchesGEOS(t, "Contains", geom.Contains, g1, g2, tt.wkt1, tt.wkt2, tt.contains)
+			})
+			t.Run("NativeCovers", func(t *testing.T) {
+				expectNativeMatchesGEOS(t, "Covers", geom.Covers, g1, g2, tt.wkt1, tt.wkt2, tt.covers)
+			})
+			t.Run("NativeIntersects", func(t *testing.T) {
+				expectNativeMatchesGEOS(t, "Intersects", geom.Intersects, g1, g2, tt.wkt1, tt.wkt2, tt.intersects)
+			})
+			t.Run("NativeWithin", func(t *testing.T) {
+				expectNativeMatchesGEOS(t, "Within", geom.Within, g1, g2, tt.wkt1, tt.wkt2, tt.within)
+			})
+			t.Run("NativeCoveredBy", func(t *testing.T) {
+				expectNativeMatchesGEOS(t, "CoveredBy", geom.CoveredBy, g1, g2, tt.wkt1, tt.wkt2, tt.coveredBy)
+			})
 		})
 	}
 }
@@ -613,3 +654,96 @@ func TestUnion(t *testing.T) {
 		})
 	}
 }
+
+// TestWKBWriterRoundTrip checks that geom.WKBWriter output re-parses back
+// to an equal geometry, both via geom.WKBReader itself and, as the real
+// acceptance check, via GEOS's own WKB reader (Handle.WKBRead), so this
+// confirms geom's writer produces bytes GEOS actually agrees with, not just
+// bytes geom's own reader can parse back.
+func TestWKBWriterRoundTrip(t *testing.T) {
+	h, err := NewHandle()
+	expectNoErr(t, err)
+	defer h.Release()
+
+	for i, wkt := range []string{
+		"POINT(1 2)",
+		"POINT EMPTY",
+		"LINESTRING(0 0,1 1,2 2)",
+		"POLYGON((0 0,0 3,3 3,3 0,0 0),(1 1,1 2,2 2,2 1,1 1))",
+		"MULTIPOINT(1 2,3 4)",
+		"MULTILINESTRING((0 0,1 1),(2 2,3 3))",
+		"MULTIPOLYGON(((0 0,0 1,1 1,1 0,0 0)),((2 2,2 3,3 3,3 2,2 2)))",
+		"GEOMETRYCOLLECTION(POINT(0 0),LINESTRING(1 0,1 1))",
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			want := geomFromWKT(t, wkt)
+
+			var buf strings.Builder
+			expectNoErr(t, geom.NewWKBWriter(&buf).Write(want))
+
+			got, srid, err := geom.NewWKBReader(strings.NewReader(buf.String())).Read()
+			expectNoErr(t, err)
+			if srid != 0 {
+				t.Errorf("got srid %d, want 0", srid)
+			}
+			expectGeomEq(t, got, want)
+
+			gotFromGEOS, err := h.WKBRead([]byte(buf.String()))
+			expectNoErr(t, err)
+			expectGeomEq(t, gotFromGEOS, want)
+		})
+	}
+}
+
+// TestWKBWriterRoundTripZM is TestWKBWriterRoundTrip's Z/M counterpart: it
+// checks that geom.WKBWriter's EWKB Z/M bit-flag output re-parses correctly
+// both through geom's own reader and through GEOS.
+func TestWKBWriterRoundTripZM(t *testing.T) {
+	h, err := NewHandle()
+	expectNoErr(t, err)
+	defer h.Release()
+
+	for i, tt := range []struct {
+		wkt string
+		ct  geom.CoordinatesType
+	}{
+		{"POINT Z(1 2 3)", geom.XYZ},
+		{"POINT M(1 2 3)", geom.XYM},
+		{"POINT ZM(1 2 3 4)", geom.XYZM},
+		{"LINESTRING Z(0 0 0,1 1 1,2 2 2)", geom.XYZ},
+		{"POLYGON Z((0 0 0,0 1 0,1 1 0,1 0 0,0 0 0))", geom.XYZ},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			want := geomFromWKT(t, tt.wkt)
+
+			var buf strings.Builder
+			expectNoErr(t, geom.NewWKBWriter(&buf, geom.OutputCoordinatesType(tt.ct)).Write(want))
+
+			got, srid, err := geom.NewWKBReader(strings.NewReader(buf.String())).Read()
+			expectNoErr(t, err)
+			if srid != 0 {
+				t.Errorf("got srid %d, want 0", srid)
+			}
+			expectGeomEq(t, got, want)
+
+			gotFromGEOS, err := h.WKBRead([]byte(buf.String()))
+			expectNoErr(t, err)
+			expectGeomEq(t, gotFromGEOS, want)
+		})
+	}
+}
+
+func TestWKBWriterRoundTripEWKB(t *testing.T) {
+	want := geomFromWKT(t, "POINT(1 2)")
+
+	var buf strings.Builder
+	wr := geom.NewWKBWriter(&buf, geom.IncludeSRID(4326), geom.HexOutput(true))
+	expectNoErr(t, wr.Write(want))
+
+	got, srid, err := geom.NewWKBReader(strings.NewReader(buf.String()), geom.HexInput(true)).Read()
+	expectNoErr(t, err)
+	if srid != 4326 {
+		t.Errorf("got srid %d, want 4326", srid)
+	}
+	expectGeomEq(t, got, want)
+}
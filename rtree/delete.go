@@ -0,0 +1,129 @@
+package rtree
+
+// minChildren is the minimum number of entries a non-root node may hold
+// before it's considered to have underflowed. It must stay in lock-step
+// with maxChildren (4) and the quadraticSplit minimum group size (2), since
+// Insert and BulkLoad both assume nodes respect this cardinality.
+const minChildren = 2
+
+// Delete removes the entry (box, recordID) from the tree, following
+// Guttman's classic R-tree deletion algorithm: the leaf holding the
+// matching entry is found by descending through every node whose box
+// contains box, the entry is removed, and condenseTree propagates the
+// resulting box shrinkage up to the root, detaching any node that
+// underflows below minChildren entries along the way. The leaf-level
+// entries belonging to detached nodes are then reinserted from the root
+// via Insert, and the root is collapsed if it ends up with a single
+// child. Delete reports whether a matching entry was found and removed.
+func (t *RTree) Delete(box Box, recordID int) bool {
+	if t.root == nil {
+		return false
+	}
+
+	leaf, idx := findLeafEntry(t.root, box, recordID)
+	if leaf == nil {
+		return false
+	}
+	removeNodeEntry(leaf, idx)
+
+	orphans := t.condenseTree(leaf)
+
+	if !t.root.isLeaf && t.root.numEntries == 1 {
+		newRoot := t.root.entries[0].child
+		newRoot.parent = nil
+		t.root = newRoot
+	} else if t.root.isLeaf && t.root.numEntries == 0 {
+		t.root = nil
+	}
+
+	for _, e := range orphans {
+		t.Insert(e.box, e.recordID)
+	}
+	return true
+}
+
+// findLeafEntry searches n and its descendants for the leaf entry matching
+// (box, recordID), descending only into children whose box contains box
+// (any node truly holding the entry must have a box covering it). It
+// returns the leaf node holding the entry and the entry's index within it,
+// or (nil, 0) if no match is found.
+func findLeafEntry(n *node, box Box, recordID int) (*node, int) {
+	if n.isLeaf {
+		for i := 0; i < n.numEntries; i++ {
+			if n.entries[i].recordID == recordID && n.entries[i].box == box {
+				return n, i
+			}
+		}
+		return nil, 0
+	}
+	for i := 0; i < n.numEntries; i++ {
+		e := n.entries[i]
+		if !boxContains(e.box, box) {
+			continue
+		}
+		if leaf, idx := findLeafEntry(e.child, box, recordID); leaf != nil {
+			return leaf, idx
+		}
+	}
+	return nil, 0
+}
+
+// boxContains reports whether outer fully contains inner.
+func boxContains(outer, inner Box) bool {
+	return outer.MinX <= inner.MinX && inner.MaxX <= outer.MaxX &&
+		outer.MinY <= inner.MinY && inner.MaxY <= outer.MaxY
+}
+
+// removeNodeEntry removes the entry at idx from n, shifting later entries
+// down to keep n's entries contiguous.
+func removeNodeEntry(n *node, idx int) {
+	for i := idx; i < n.numEntries-1; i++ {
+		n.entries[i] = n.entries[i+1]
+	}
+	n.entries[n.numEntries-1] = entry{}
+	n.numEntries--
+}
+
+// condenseTree walks from n up to the root, tightening each ancestor's
+// bounding box to account for the removal that occurred at n. Any node
+// that has underflowed (fewer than minChildren entries) is detached from
+// its parent instead of having its box tightened, and all of the
+// leaf-level (box, recordID) entries beneath it are flattened out and
+// returned so the caller can reinsert them from the root with Insert.
+func (t *RTree) condenseTree(n *node) []entry {
+	var orphans []entry
+	for n.parent != nil {
+		parent := n.parent
+		if n.numEntries < minChildren {
+			removeChildEntry(parent, n)
+			orphans = append(orphans, collectLeafEntries(n)...)
+		} else {
+			updateChildBox(parent, n)
+		}
+		n = parent
+	}
+	return orphans
+}
+
+// removeChildEntry removes the entry in parent whose child is n.
+func removeChildEntry(parent, n *node) {
+	for i := 0; i < parent.numEntries; i++ {
+		if parent.entries[i].child == n {
+			removeNodeEntry(parent, i)
+			return
+		}
+	}
+}
+
+// collectLeafEntries flattens n's subtree into the list of leaf-level
+// entries it contains.
+func collectLeafEntries(n *node) []entry {
+	if n.isLeaf {
+		return append([]entry(nil), n.entries[:n.numEntries]...)
+	}
+	var entries []entry
+	for i := 0; i < n.numEntries; i++ {
+		entries = append(entries, collectLeafEntries(n.entries[i].child)...)
+	}
+	return entries
+}
@@ -0,0 +1,221 @@
+package rtree
+
+import "math"
+
+// Insert adds a new entry (box, recordID) to the tree, following Guttman's
+// classic R-tree insertion algorithm: ChooseLeaf descends the tree picking,
+// at each level, the child that would need the least enlargement to cover
+// box (ties broken by the child with the smaller existing area), and the
+// new entry is added there. If that leaf is already at capacity, it's split
+// in two using a quadratic split, and the split is propagated up the tree,
+// growing a new root if the existing root itself ends up being split.
+func (t *RTree) Insert(box Box, recordID int) {
+	if t.root == nil {
+		t.root = &node{isLeaf: true}
+	}
+	leaf := chooseLeaf(t.root, box)
+	split := addNodeEntry(leaf, entry{box: box, recordID: recordID})
+	t.adjustTree(leaf, split)
+}
+
+// chooseLeaf descends from n to the leaf best suited to receive box.
+func chooseLeaf(n *node, box Box) *node {
+	for !n.isLeaf {
+		best := 0
+		bestEnlargement := math.Inf(+1)
+		bestArea := math.Inf(+1)
+		for i := 0; i < n.numEntries; i++ {
+			e := n.entries[i]
+			enlargement := boxArea(combine(e.box, box)) - boxArea(e.box)
+			if enlargement < bestEnlargement ||
+				(enlargement == bestEnlargement && boxArea(e.box) < bestArea) {
+				best = i
+				bestEnlargement = enlargement
+				bestArea = boxArea(e.box)
+			}
+		}
+		n = n.entries[best].child
+	}
+	return n
+}
+
+// addNodeEntry adds e to n. If n isn't yet at capacity, e is simply
+// appended and nil is returned. Otherwise n is split in two using
+// quadraticSplit: n keeps one of the resulting groups of entries, and the
+// other group is returned as a new sibling node (same isLeaf-ness as n,
+// parent not yet set) for the caller to link into the tree.
+func addNodeEntry(n *node, e entry) *node {
+	if n.numEntries < maxChildren {
+		n.entries[n.numEntries] = e
+		n.numEntries++
+		if e.child != nil {
+			e.child.parent = n
+		}
+		return nil
+	}
+
+	all := make([]entry, 0, maxChildren+1)
+	all = append(all, n.entries[:n.numEntries]...)
+	all = append(all, e)
+	groupA, groupB := quadraticSplit(all)
+
+	n.numEntries = 0
+	for _, g := range groupA {
+		n.entries[n.numEntries] = g
+		n.numEntries++
+		if g.child != nil {
+			g.child.parent = n
+		}
+	}
+
+	sib := &node{isLeaf: n.isLeaf}
+	for _, g := range groupB {
+		sib.entries[sib.numEntries] = g
+		sib.numEntries++
+		if g.child != nil {
+			g.child.parent = sib
+		}
+	}
+	return sib
+}
+
+// adjustTree propagates n's updated bounding box, and any split produced at
+// n (non-nil split), up the tree to the root. If the root itself ends up
+// being split, a new root is grown above it.
+func (t *RTree) adjustTree(n, split *node) {
+	for {
+		parent := n.parent
+		if parent == nil {
+			if split != nil {
+				t.growRoot(n, split)
+			}
+			return
+		}
+
+		updateChildBox(parent, n)
+
+		var parentSplit *node
+		if split != nil {
+			parentSplit = addNodeEntry(parent, entry{box: calculateBound(split), child: split})
+		}
+
+		n, split = parent, parentSplit
+	}
+}
+
+// growRoot creates a new root above oldRoot (the previous root, which has
+// just been split into itself and newSibling), restoring the invariant
+// that the tree has a single top-level node.
+func (t *RTree) growRoot(oldRoot, newSibling *node) {
+	root := &node{
+		numEntries: 2,
+		entries: [maxChildren]entry{
+			{box: calculateBound(oldRoot), child: oldRoot},
+			{box: calculateBound(newSibling), child: newSibling},
+		},
+	}
+	oldRoot.parent = root
+	newSibling.parent = root
+	t.root = root
+}
+
+// updateChildBox recomputes child's bounding box and stores it in its
+// corresponding entry within parent.
+func updateChildBox(parent, child *node) {
+	for i := 0; i < parent.numEntries; i++ {
+		if parent.entries[i].child == child {
+			parent.entries[i].box = calculateBound(child)
+			return
+		}
+	}
+}
+
+// quadraticSplit splits all (maxChildren+1 entries) into two groups using
+// Guttman's quadratic split algorithm: the pair of entries that would waste
+// the most area if placed in the same group (pickSeeds) become the seeds of
+// each group, and the remaining entries are then assigned one at a time
+// (pickNext) to whichever group's bounding box would need to grow the
+// least to accommodate them - except that once one group has few enough
+// slots left that every remaining entry must go to it to satisfy the
+// minimum of 2 entries per group, the rest are assigned there directly.
+func quadraticSplit(all []entry) ([]entry, []entry) {
+	si, sj := pickSeeds(all)
+	groupA := []entry{all[si]}
+	groupB := []entry{all[sj]}
+	boxA := all[si].box
+	boxB := all[sj].box
+
+	var remaining []entry
+	for k, e := range all {
+		if k != si && k != sj {
+			remaining = append(remaining, e)
+		}
+	}
+
+	const minGroup = 2
+	for len(remaining) > 0 {
+		total := len(groupA) + len(groupB) + len(remaining)
+		if len(groupA)+len(remaining) <= minGroup || total-len(groupB) <= minGroup {
+			groupA = append(groupA, remaining...)
+			break
+		}
+		if len(groupB)+len(remaining) <= minGroup {
+			groupB = append(groupB, remaining...)
+			break
+		}
+
+		idx, toA := pickNext(remaining, boxA, boxB)
+		e := remaining[idx]
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+		if toA {
+			groupA = append(groupA, e)
+			boxA = combine(boxA, e.box)
+		} else {
+			groupB = append(groupB, e)
+			boxB = combine(boxB, e.box)
+		}
+	}
+	return groupA, groupB
+}
+
+// pickSeeds finds the pair of entries in all that would waste the most area
+// (area of their combined box, minus the area of each individually) if put
+// in the same group.
+func pickSeeds(all []entry) (int, int) {
+	bestI, bestJ := 0, 1
+	bestWaste := math.Inf(-1)
+	for i := 0; i < len(all); i++ {
+		for j := i + 1; j < len(all); j++ {
+			waste := boxArea(combine(all[i].box, all[j].box)) - boxArea(all[i].box) - boxArea(all[j].box)
+			if waste > bestWaste {
+				bestWaste = waste
+				bestI, bestJ = i, j
+			}
+		}
+	}
+	return bestI, bestJ
+}
+
+// pickNext picks the entry in remaining whose preference for one group over
+// the other (measured by the difference in area enlargement needed to add
+// it to boxA vs boxB) is strongest, and reports which group it prefers.
+func pickNext(remaining []entry, boxA, boxB Box) (int, bool) {
+	bestIdx := 0
+	bestDiff := math.Inf(-1)
+	var toA bool
+	for idx, e := range remaining {
+		dA := boxArea(combine(boxA, e.box)) - boxArea(boxA)
+		dB := boxArea(combine(boxB, e.box)) - boxArea(boxB)
+		diff := math.Abs(dA - dB)
+		if diff > bestDiff {
+			bestDiff = diff
+			bestIdx = idx
+			toA = dA < dB
+		}
+	}
+	return bestIdx, toA
+}
+
+func boxArea(b Box) float64 {
+	return (b.MaxX - b.MinX) * (b.MaxY - b.MinY)
+}
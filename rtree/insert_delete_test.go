@@ -0,0 +1,106 @@
+package rtree_test
+
+import (
+	"testing"
+
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+// collectKNN drains every entry out of tr via KNN (which visits every entry
+// if never told to stop), returning the set of RecordIDs seen.
+func collectKNN(tr *rtree.RTree) map[int]bool {
+	seen := make(map[int]bool)
+	tr.KNN(ptBox(0, 0), func(recordID int, dist float64) bool {
+		seen[recordID] = true
+		return true
+	})
+	return seen
+}
+
+func TestInsertIncrementally(t *testing.T) {
+	tr := &rtree.RTree{}
+	const n = 20
+	for i := 0; i < n; i++ {
+		tr.Insert(ptBox(float64(i), float64(i)), i)
+	}
+
+	seen := collectKNN(tr)
+	if len(seen) != n {
+		t.Fatalf("got %d entries after inserting %d, want %d", len(seen), n, n)
+	}
+	for i := 0; i < n; i++ {
+		if !seen[i] {
+			t.Errorf("RecordID %d missing after incremental Insert", i)
+		}
+	}
+}
+
+func TestDeleteRemovesEntry(t *testing.T) {
+	tr := &rtree.RTree{}
+	const n = 10
+	for i := 0; i < n; i++ {
+		tr.Insert(ptBox(float64(i), 0), i)
+	}
+
+	if ok := tr.Delete(ptBox(5, 0), 5); !ok {
+		t.Fatalf("Delete reported the entry wasn't found")
+	}
+
+	seen := collectKNN(tr)
+	if len(seen) != n-1 {
+		t.Fatalf("got %d entries after deleting one of %d, want %d", len(seen), n, n-1)
+	}
+	if seen[5] {
+		t.Errorf("deleted RecordID 5 still present")
+	}
+	for i := 0; i < n; i++ {
+		if i != 5 && !seen[i] {
+			t.Errorf("RecordID %d missing after deleting an unrelated entry", i)
+		}
+	}
+}
+
+func TestDeleteMissingEntryReportsFalse(t *testing.T) {
+	tr := &rtree.RTree{}
+	tr.Insert(ptBox(0, 0), 0)
+
+	if ok := tr.Delete(ptBox(99, 99), 1); ok {
+		t.Errorf("Delete reported success for an entry that was never inserted")
+	}
+}
+
+func TestDeleteFromEmptyTree(t *testing.T) {
+	tr := &rtree.RTree{}
+	if ok := tr.Delete(ptBox(0, 0), 0); ok {
+		t.Errorf("Delete reported success on an empty tree")
+	}
+}
+
+func TestInsertDeleteManyTriggersSplitsAndCondensing(t *testing.T) {
+	// Enough entries that Insert must split nodes, and deleting most of
+	// them back out must trigger condenseTree's reinsertion path.
+	tr := &rtree.RTree{}
+	const n = 100
+	for i := 0; i < n; i++ {
+		tr.Insert(ptBox(float64(i), float64(-i)), i)
+	}
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			continue
+		}
+		if ok := tr.Delete(ptBox(float64(i), float64(-i)), i); !ok {
+			t.Fatalf("failed to delete RecordID %d", i)
+		}
+	}
+
+	seen := collectKNN(tr)
+	if len(seen) != n/2 {
+		t.Fatalf("got %d entries remaining, want %d", len(seen), n/2)
+	}
+	for i := 0; i < n; i++ {
+		want := i%2 == 0
+		if seen[i] != want {
+			t.Errorf("RecordID %d: present=%v, want %v", i, seen[i], want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package rtree
+
+import "container/heap"
+
+// KNN performs a best-first nearest-neighbours search, visiting entries in
+// order of increasing squared distance from target. For each leaf entry
+// visited, iter is called with its RecordID and its squared distance from
+// target; iter should return true to keep visiting further (more distant)
+// entries, or false to stop the search early.
+//
+// The search proceeds with a min-heap priority queue keyed on squared
+// distance: the root's children are pushed onto the heap, and then the
+// closest element is repeatedly popped off. If it's an internal node, its
+// children are pushed (each keyed on its own box's squared distance from
+// target); if it's a leaf entry, it's yielded to iter. Because the heap
+// always pops the globally closest remaining box first, and a node's box
+// strictly contains all of its descendants, every entry is yielded in
+// non-decreasing distance order.
+//
+// target may be a point (MinX == MaxX and MinY == MaxY), in which case the
+// search degenerates to a point-to-box nearest neighbours search.
+func (t *RTree) KNN(target Box, iter func(recordID int, dist float64) bool) {
+	if t.root == nil {
+		return
+	}
+
+	pq := &knnQueue{{node: t.root}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(knnItem)
+		if item.isEntry {
+			if !iter(item.recordID, item.dist) {
+				return
+			}
+			continue
+		}
+		node := item.node
+		for i := 0; i < node.numEntries; i++ {
+			e := node.entries[i]
+			dist := boxDistanceSq(e.box, target)
+			if node.isLeaf {
+				heap.Push(pq, knnItem{isEntry: true, recordID: e.recordID, dist: dist})
+			} else {
+				heap.Push(pq, knnItem{node: e.child, dist: dist})
+			}
+		}
+	}
+}
+
+// knnItem is an entry in KNN's priority queue: either an unexpanded node
+// (isEntry false) or a leaf entry ready to be yielded to the caller
+// (isEntry true).
+type knnItem struct {
+	dist     float64
+	node     *node
+	recordID int
+	isEntry  bool
+}
+
+type knnQueue []knnItem
+
+func (q knnQueue) Len() int            { return len(q) }
+func (q knnQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q knnQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *knnQueue) Push(x interface{}) { *q = append(*q, x.(knnItem)) }
+func (q *knnQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
+
+// boxDistanceSq computes the squared distance between boxes a and b: 0 if
+// they overlap (in either axis' case, their ranges intersect), otherwise
+// the sum of the squared gaps between their non-overlapping axes. This
+// also covers the point case (where a box's Min and Max are equal).
+func boxDistanceSq(a, b Box) float64 {
+	dx := axisGap(a.MinX, a.MaxX, b.MinX, b.MaxX)
+	dy := axisGap(a.MinY, a.MaxY, b.MinY, b.MaxY)
+	return dx*dx + dy*dy
+}
+
+// axisGap returns the gap between the ranges [aMin, aMax] and [bMin, bMax],
+// or 0 if they overlap.
+func axisGap(aMin, aMax, bMin, bMax float64) float64 {
+	switch {
+	case aMax < bMin:
+		return bMin - aMax
+	case bMax < aMin:
+		return aMin - bMax
+	default:
+		return 0
+	}
+}
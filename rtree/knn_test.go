@@ -0,0 +1,74 @@
+package rtree_test
+
+import (
+	"testing"
+
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+func ptBox(x, y float64) rtree.Box {
+	return rtree.Box{MinX: x, MinY: y, MaxX: x, MaxY: y}
+}
+
+func TestKNNVisitsInIncreasingDistanceOrder(t *testing.T) {
+	items := []rtree.BulkItem{
+		{Box: ptBox(0, 0), RecordID: 0},
+		{Box: ptBox(10, 0), RecordID: 1},
+		{Box: ptBox(3, 4), RecordID: 2},  // distance 5 from origin
+		{Box: ptBox(1, 1), RecordID: 3},  // distance sqrt(2) from origin
+		{Box: ptBox(-5, 0), RecordID: 4}, // distance 5 from origin
+	}
+	tr := rtree.BulkLoad(items)
+
+	var order []int
+	var dists []float64
+	tr.KNN(ptBox(0, 0), func(recordID int, dist float64) bool {
+		order = append(order, recordID)
+		dists = append(dists, dist)
+		return true
+	})
+
+	if len(order) != len(items) {
+		t.Fatalf("got %d results, want %d", len(order), len(items))
+	}
+	for i := 1; i < len(dists); i++ {
+		if dists[i] < dists[i-1] {
+			t.Errorf("results not in non-decreasing distance order: %v", dists)
+			break
+		}
+	}
+	if order[0] != 0 {
+		t.Errorf("nearest result should be the origin itself (RecordID 0), got %d", order[0])
+	}
+}
+
+func TestKNNStopsEarly(t *testing.T) {
+	items := []rtree.BulkItem{
+		{Box: ptBox(0, 0), RecordID: 0},
+		{Box: ptBox(1, 0), RecordID: 1},
+		{Box: ptBox(2, 0), RecordID: 2},
+		{Box: ptBox(3, 0), RecordID: 3},
+	}
+	tr := rtree.BulkLoad(items)
+
+	var visited int
+	tr.KNN(ptBox(0, 0), func(recordID int, dist float64) bool {
+		visited++
+		return visited < 2
+	})
+	if visited != 2 {
+		t.Errorf("got %d visits, want exactly 2 (search should stop once iter returns false)", visited)
+	}
+}
+
+func TestKNNEmptyTree(t *testing.T) {
+	tr := rtree.BulkLoad(nil)
+	called := false
+	tr.KNN(ptBox(0, 0), func(recordID int, dist float64) bool {
+		called = true
+		return true
+	})
+	if called {
+		t.Errorf("KNN on an empty tree should never call iter")
+	}
+}
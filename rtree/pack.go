@@ -0,0 +1,209 @@
+package rtree
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Binary format constants. The format is deliberately simple: a fixed-size
+// header followed by a flat run of fixed-size node records, so that a
+// packed tree can be mmap'd and its pointer graph rebuilt by resolving byte
+// offsets, without needing to parse a variable-length encoding.
+const (
+	packMagic   = 0x45525452 // "RTRE" as little-endian bytes.
+	packVersion = 1
+	packDims    = 2
+
+	headerSize = 4*6 + 0 // magic, version, dims, maxChildren, numItems, numLevels (uint32 each)
+	entrySize  = 4*8 + 8 // MinX, MinY, MaxX, MaxY (float64) + payload (int64)
+	nodeSize   = 2 + maxChildren*entrySize
+)
+
+// MarshalBinary encodes t into a compact, versioned, little-endian binary
+// format: a header (magic, version, dims, maxChildren, numItems, numLevels)
+// followed by every node in the tree, level by level, each encoded as
+// {isLeaf byte, numEntries byte, entries: [maxChildren]{MinX, MinY, MaxX,
+// MaxY float64, payload int64}}. payload is the recordID for entries in a
+// leaf node, and the byte offset of the child node for entries in an
+// internal node. The result is self-contained and can be handed directly
+// to UnmarshalBinary, including by mmap'ing it from disk.
+func (t *RTree) MarshalBinary() ([]byte, error) {
+	if t.root == nil {
+		buf := make([]byte, headerSize)
+		putHeader(buf, 0, 0)
+		return buf, nil
+	}
+
+	nodes, numItems, numLevels := levelOrder(t.root)
+
+	buf := make([]byte, headerSize+len(nodes)*nodeSize)
+	putHeader(buf, numItems, numLevels)
+
+	offsets := make(map[*node]int64, len(nodes))
+	for i, n := range nodes {
+		offsets[n] = int64(headerSize + i*nodeSize)
+	}
+	for i, n := range nodes {
+		off := headerSize + i*nodeSize
+		putNode(buf[off:off+nodeSize], n, offsets)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes data (as produced by (*RTree).MarshalBinary) into
+// a freshly built RTree, rebuilding the pointer graph from the encoded
+// child offsets in a single recursive pass.
+func UnmarshalBinary(data []byte) (*RTree, error) {
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("rtree: data too short to contain a header: got %d bytes, want at least %d", len(data), headerSize)
+	}
+	if magic := binary.LittleEndian.Uint32(data[0:4]); magic != packMagic {
+		return nil, fmt.Errorf("rtree: bad magic number: got %#x, want %#x", magic, packMagic)
+	}
+	if version := binary.LittleEndian.Uint32(data[4:8]); version != packVersion {
+		return nil, fmt.Errorf("rtree: unsupported format version: got %d, want %d", version, packVersion)
+	}
+	if dims := binary.LittleEndian.Uint32(data[8:12]); dims != packDims {
+		return nil, fmt.Errorf("rtree: unsupported dimension count: got %d, want %d", dims, packDims)
+	}
+	if packed := binary.LittleEndian.Uint32(data[12:16]); packed != maxChildren {
+		return nil, fmt.Errorf("rtree: maxChildren mismatch: data was packed with %d, this build uses %d", packed, maxChildren)
+	}
+	numItems := binary.LittleEndian.Uint32(data[16:20])
+	numLevels := binary.LittleEndian.Uint32(data[20:24])
+
+	if numItems == 0 && numLevels == 0 {
+		return &RTree{}, nil
+	}
+
+	root, err := unpackNode(data, headerSize, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RTree{root: root}, nil
+}
+
+// WriteTo writes t's MarshalBinary encoding to w, implementing io.WriterTo.
+func (t *RTree) WriteTo(w io.Writer) (int64, error) {
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom replaces t's contents with the tree encoded in r (as produced by
+// WriteTo or MarshalBinary), implementing io.ReaderFrom.
+func (t *RTree) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	unmarshalled, err := UnmarshalBinary(data)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	*t = *unmarshalled
+	return int64(len(data)), nil
+}
+
+// putHeader writes the fixed header fields into the start of buf.
+func putHeader(buf []byte, numItems, numLevels int) {
+	binary.LittleEndian.PutUint32(buf[0:4], packMagic)
+	binary.LittleEndian.PutUint32(buf[4:8], packVersion)
+	binary.LittleEndian.PutUint32(buf[8:12], packDims)
+	binary.LittleEndian.PutUint32(buf[12:16], maxChildren)
+	binary.LittleEndian.PutUint32(buf[16:20], uint32(numItems))
+	binary.LittleEndian.PutUint32(buf[20:24], uint32(numLevels))
+}
+
+// levelOrder flattens root's subtree into a single level-by-level slice
+// (root first, then its children, then their children, and so on), along
+// with the total number of leaf-level entries and the number of levels in
+// the tree. Encoding nodes in this order means every child offset an
+// internal node needs to record has already been laid out by the time that
+// node's children are visited.
+func levelOrder(root *node) ([]*node, int, int) {
+	var nodes []*node
+	numItems := 0
+	numLevels := 0
+	level := []*node{root}
+	for len(level) > 0 {
+		numLevels++
+		var next []*node
+		for _, n := range level {
+			nodes = append(nodes, n)
+			if n.isLeaf {
+				numItems += n.numEntries
+			} else {
+				for i := 0; i < n.numEntries; i++ {
+					next = append(next, n.entries[i].child)
+				}
+			}
+		}
+		level = next
+	}
+	return nodes, numItems, numLevels
+}
+
+// putNode encodes n into dst, which must be exactly nodeSize bytes. Entry
+// slots beyond n.numEntries are left zeroed. offsets gives the byte offset
+// that's already been assigned to every node in the tree, used to resolve
+// child pointers into payloads.
+func putNode(dst []byte, n *node, offsets map[*node]int64) {
+	if n.isLeaf {
+		dst[0] = 1
+	}
+	dst[1] = byte(n.numEntries)
+	for i := 0; i < n.numEntries; i++ {
+		e := n.entries[i]
+		off := 2 + i*entrySize
+		binary.LittleEndian.PutUint64(dst[off:], math.Float64bits(e.box.MinX))
+		binary.LittleEndian.PutUint64(dst[off+8:], math.Float64bits(e.box.MinY))
+		binary.LittleEndian.PutUint64(dst[off+16:], math.Float64bits(e.box.MaxX))
+		binary.LittleEndian.PutUint64(dst[off+24:], math.Float64bits(e.box.MaxY))
+		payload := int64(e.recordID)
+		if !n.isLeaf {
+			payload = offsets[e.child]
+		}
+		binary.LittleEndian.PutUint64(dst[off+32:], uint64(payload))
+	}
+}
+
+// unpackNode decodes the node at byte offset off within data, recursively
+// decoding its children (for an internal node) and linking parent back to
+// each of them.
+func unpackNode(data []byte, off int64, parent *node) (*node, error) {
+	if off < 0 || off+nodeSize > int64(len(data)) {
+		return nil, fmt.Errorf("rtree: node offset %d out of range for %d byte buffer", off, len(data))
+	}
+	raw := data[off : off+nodeSize]
+	n := &node{
+		isLeaf:     raw[0] == 1,
+		numEntries: int(raw[1]),
+		parent:     parent,
+	}
+	for i := 0; i < n.numEntries; i++ {
+		eoff := 2 + i*entrySize
+		box := Box{
+			MinX: math.Float64frombits(binary.LittleEndian.Uint64(raw[eoff:])),
+			MinY: math.Float64frombits(binary.LittleEndian.Uint64(raw[eoff+8:])),
+			MaxX: math.Float64frombits(binary.LittleEndian.Uint64(raw[eoff+16:])),
+			MaxY: math.Float64frombits(binary.LittleEndian.Uint64(raw[eoff+24:])),
+		}
+		payload := int64(binary.LittleEndian.Uint64(raw[eoff+32:]))
+		if n.isLeaf {
+			n.entries[i] = entry{box: box, recordID: int(payload)}
+			continue
+		}
+		child, err := unpackNode(data, payload, n)
+		if err != nil {
+			return nil, err
+		}
+		n.entries[i] = entry{box: box, child: child}
+	}
+	return n, nil
+}
@@ -0,0 +1,95 @@
+package rtree_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+func TestMarshalUnmarshalBinaryRoundTrip(t *testing.T) {
+	items := make([]rtree.BulkItem, 50)
+	for i := range items {
+		items[i] = rtree.BulkItem{Box: ptBox(float64(i), float64(-i)), RecordID: i}
+	}
+	tr := rtree.BulkLoad(items)
+
+	data, err := tr.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := rtree.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	seen := collectKNN(got)
+	if len(seen) != len(items) {
+		t.Fatalf("got %d entries after round-tripping, want %d", len(seen), len(items))
+	}
+	for i := range items {
+		if !seen[i] {
+			t.Errorf("RecordID %d missing after round-tripping", i)
+		}
+	}
+}
+
+func TestMarshalUnmarshalBinaryEmptyTree(t *testing.T) {
+	tr := rtree.BulkLoad(nil)
+
+	data, err := tr.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := rtree.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(collectKNN(got)) != 0 {
+		t.Errorf("expected no entries after round-tripping an empty tree")
+	}
+}
+
+func TestUnmarshalBinaryRejectsTruncatedData(t *testing.T) {
+	if _, err := rtree.UnmarshalBinary([]byte{1, 2, 3}); err == nil {
+		t.Errorf("expected an error unmarshalling data too short to contain a header")
+	}
+}
+
+func TestUnmarshalBinaryRejectsBadMagic(t *testing.T) {
+	tr := rtree.BulkLoad([]rtree.BulkItem{{Box: ptBox(0, 0), RecordID: 0}})
+	data, err := tr.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[0] ^= 0xFF
+	if _, err := rtree.UnmarshalBinary(corrupted); err == nil {
+		t.Errorf("expected an error unmarshalling data with a corrupted magic number")
+	}
+}
+
+func TestWriteToReadFromRoundTrip(t *testing.T) {
+	tr := rtree.BulkLoad([]rtree.BulkItem{
+		{Box: ptBox(0, 0), RecordID: 0},
+		{Box: ptBox(1, 1), RecordID: 1},
+	})
+
+	var buf bytes.Buffer
+	if _, err := tr.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got rtree.RTree
+	if _, err := got.ReadFrom(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	seen := collectKNN(&got)
+	if len(seen) != 2 || !seen[0] || !seen[1] {
+		t.Errorf("got %v after WriteTo/ReadFrom round-trip, want {0, 1}", seen)
+	}
+}
@@ -0,0 +1,57 @@
+package rtree
+
+import (
+	"container/heap"
+	"errors"
+)
+
+// Stop is returned by a PrioritySearch callback to end the search early.
+// PrioritySearch itself returns nil when its callback returns Stop (it's a
+// signal, not a real failure); any other error returned by the callback is
+// passed back to PrioritySearch's own caller unchanged.
+var Stop = errors.New("rtree: stop")
+
+// PrioritySearch visits t's entries in order of increasing squared distance
+// from box (0 for any entry whose own box overlaps box), calling fn with
+// each visited entry's RecordID. It stops once every entry has been
+// visited, or as soon as fn returns a non-nil error.
+//
+// PrioritySearch shares KNN's best-first heap traversal (see knn.go), but
+// yields RecordIDs via an error-returning callback rather than KNN's
+// bool-returning one, which lets a caller abort early with a specific
+// sentinel (Stop) instead of overloading "keep going" as a plain bool.
+//
+// box may be a point (MinX == MaxX and MinY == MaxY), in which case the
+// search degenerates to a point-to-box nearest neighbours search, as with
+// KNN.
+func (t *RTree) PrioritySearch(box Box, fn func(recordID int) error) error {
+	if t.root == nil {
+		return nil
+	}
+
+	pq := &knnQueue{{node: t.root}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(knnItem)
+		if item.isEntry {
+			switch err := fn(item.recordID); {
+			case err == nil:
+				continue
+			case errors.Is(err, Stop):
+				return nil
+			default:
+				return err
+			}
+		}
+		node := item.node
+		for i := 0; i < node.numEntries; i++ {
+			e := node.entries[i]
+			dist := boxDistanceSq(e.box, box)
+			if node.isLeaf {
+				heap.Push(pq, knnItem{isEntry: true, recordID: e.recordID, dist: dist})
+			} else {
+				heap.Push(pq, knnItem{node: e.child, dist: dist})
+			}
+		}
+	}
+	return nil
+}
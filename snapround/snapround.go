@@ -0,0 +1,316 @@
+// Package snapround implements Hobby-style snap rounding: a noding
+// technique that rounds every input vertex and every pairwise segment
+// intersection onto a regular grid, then splits every segment passing
+// through a "hot pixel" (a grid cell guaranteed to contain a node of the
+// noded arrangement) there. This produces a topologically consistent
+// noding even for inputs whose intersections can't be computed exactly in
+// floating point, which is a well-known source of robustness failures in
+// naive overlay noding.
+package snapround
+
+import (
+	"math"
+	"sort"
+
+	"github.com/peterstace/simplefeatures/geom"
+	"github.com/peterstace/simplefeatures/rtree"
+)
+
+// SnapGrid specifies the precision grid that SnapRound rounds geometry onto:
+// every coordinate is snapped to the nearest multiple of Size.
+type SnapGrid struct {
+	Size float64
+}
+
+func (g SnapGrid) round(xy geom.XY) geom.XY {
+	return geom.XY{
+		X: math.Round(xy.X/g.Size) * g.Size,
+		Y: math.Round(xy.Y/g.Size) * g.Size,
+	}
+}
+
+// SnapRound computes a snap-rounded noding of g against grid, returning the
+// noded result as a MultiLineString together with the set of hot pixels
+// (grid points guaranteed to be nodes of the result). The hot pixel set is
+// returned in the same map[geom.XY]struct{} shape that simplefeatures'
+// overlay machinery uses as its "interactions" input, so that a
+// snap-rounded geometry can be fed into the overlay operations as a
+// robustness pre-processing pass. An input with no segments (e.g. only
+// Points) is returned unchanged, with a nil hot pixel set.
+//
+// The algorithm:
+//
+//  1. Index every segment of g in an R-tree.
+//  2. Find every pairwise intersection between those segments.
+//  3. Round every original segment endpoint, and every intersection point,
+//     onto grid; each distinct rounded point is a hot pixel.
+//  4. For every segment, find (via the R-tree) every hot pixel whose L∞
+//     square the segment passes through, and split the segment there.
+//  5. Emit the split segments as a MultiLineString, along with the hot
+//     pixel set.
+func SnapRound(g geom.Geometry, grid SnapGrid) (geom.Geometry, map[geom.XY]struct{}) {
+	segs := extractSegments(g)
+	if len(segs) == 0 {
+		return g, nil
+	}
+
+	segIndex := indexSegments(segs)
+
+	hotSet := make(map[geom.XY]struct{})
+	for _, s := range segs {
+		hotSet[grid.round(s.a)] = struct{}{}
+		hotSet[grid.round(s.b)] = struct{}{}
+	}
+	for _, pt := range findIntersections(segs, segIndex) {
+		hotSet[grid.round(pt)] = struct{}{}
+	}
+
+	pixels := make([]geom.XY, 0, len(hotSet))
+	for p := range hotSet {
+		pixels = append(pixels, p)
+	}
+	pixelIndex := buildHotPixelIndex(pixels)
+
+	var coords [][]geom.Coordinates
+	for _, s := range segs {
+		chain := snapSegment(s, grid, pixels, pixelIndex)
+		if len(chain) < 2 {
+			continue
+		}
+		cs := make([]geom.Coordinates, len(chain))
+		for i, xy := range chain {
+			cs[i] = geom.Coordinates{XY: xy}
+		}
+		coords = append(coords, cs)
+	}
+
+	mls, err := geom.NewMultiLineStringC(coords)
+	if err != nil {
+		panic(err)
+	}
+	return mls, hotSet
+}
+
+// AsNoder adapts SnapRound into the shape geom.OverlayOptions.Noder expects,
+// so that it can be wired into the overlay pipeline as a robustness
+// pre-pass:
+//
+//	geom.Union(a, b, geom.OverlayOptions{Noder: snapround.AsNoder(grid)})
+//
+// It runs SnapRound over both operands combined (as a GeometryCollection,
+// so that crossings between a segment of a and a segment of b are found
+// and become hot pixels too), and returns just the hot pixel set: the
+// overlay pipeline's own noding still runs, but now treats every hot pixel
+// as a point it must node at, which is what actually pulls together the
+// nearly-coincident vertices and intersections that naive floating-point
+// noding trips over.
+func AsNoder(grid SnapGrid) func(a, b geom.Geometry) map[geom.XY]struct{} {
+	return func(a, b geom.Geometry) map[geom.XY]struct{} {
+		_, hotPixels := SnapRound(geom.NewGeometryCollection([]geom.Geometry{a, b}), grid)
+		return hotPixels
+	}
+}
+
+// segment is a single line segment extracted from the input geometry.
+type segment struct {
+	a, b geom.XY
+}
+
+// extractSegments walks g, collecting every line segment making up its
+// LineStrings, MultiLineStrings, Polygon/MultiPolygon ring boundaries, and
+// (recursively) GeometryCollection elements. Points contribute no segments.
+func extractSegments(g geom.Geometry) []segment {
+	var segs []segment
+	switch g.Type() {
+	case geom.TypeLineString:
+		segs = append(segs, sequenceSegments(g.AsLineString().Coordinates())...)
+	case geom.TypeMultiLineString:
+		mls := g.AsMultiLineString()
+		for i := 0; i < mls.NumLineStrings(); i++ {
+			segs = append(segs, sequenceSegments(mls.LineStringN(i).Coordinates())...)
+		}
+	case geom.TypePolygon:
+		segs = append(segs, polygonSegments(g.AsPolygon())...)
+	case geom.TypeMultiPolygon:
+		mp := g.AsMultiPolygon()
+		for i := 0; i < mp.NumPolygons(); i++ {
+			segs = append(segs, polygonSegments(mp.PolygonN(i))...)
+		}
+	case geom.TypeGeometryCollection:
+		gc := g.AsGeometryCollection()
+		for i := 0; i < gc.NumGeometries(); i++ {
+			segs = append(segs, extractSegments(gc.GeometryN(i))...)
+		}
+	}
+	return segs
+}
+
+func polygonSegments(p geom.Polygon) []segment {
+	segs := sequenceSegments(p.ExteriorRing().Coordinates())
+	for i := 0; i < p.NumInteriorRings(); i++ {
+		segs = append(segs, sequenceSegments(p.InteriorRingN(i).Coordinates())...)
+	}
+	return segs
+}
+
+func sequenceSegments(seq geom.Sequence) []segment {
+	n := seq.Length()
+	if n < 2 {
+		return nil
+	}
+	segs := make([]segment, 0, n-1)
+	for i := 0; i < n-1; i++ {
+		segs = append(segs, segment{seq.GetXY(i), seq.GetXY(i + 1)})
+	}
+	return segs
+}
+
+func segmentBox(s segment) rtree.Box {
+	return rtree.Box{
+		MinX: math.Min(s.a.X, s.b.X),
+		MinY: math.Min(s.a.Y, s.b.Y),
+		MaxX: math.Max(s.a.X, s.b.X),
+		MaxY: math.Max(s.a.Y, s.b.Y),
+	}
+}
+
+func indexSegments(segs []segment) *rtree.RTree {
+	items := make([]rtree.BulkItem, len(segs))
+	for i, s := range segs {
+		items[i] = rtree.BulkItem{Box: segmentBox(s), RecordID: i}
+	}
+	return rtree.BulkLoad(items)
+}
+
+// findIntersections returns the proper crossing point of every pair of
+// segments in segs that actually cross, found with the help of segIndex.
+// Collinear overlaps are skipped: both segments involved already contribute
+// their own (rounded) endpoints as hot pixels, which is enough to node them
+// against each other once step 4 of SnapRound splits segments at hot
+// pixels.
+func findIntersections(segs []segment, segIndex *rtree.RTree) []geom.XY {
+	var pts []geom.XY
+	for i, s := range segs {
+		segIndex.PrioritySearch(segmentBox(s), func(j int) error {
+			if j > i {
+				if pt, ok := properIntersection(s, segs[j]); ok {
+					pts = append(pts, pt)
+				}
+			}
+			return nil
+		})
+	}
+	return pts
+}
+
+// properIntersection returns the single point where segments s and t cross,
+// if any.
+func properIntersection(s, t segment) (geom.XY, bool) {
+	r := s.b.Sub(s.a)
+	q := t.b.Sub(t.a)
+	denom := cross(r, q)
+	if denom == 0 {
+		return geom.XY{}, false // parallel or collinear
+	}
+	qp := t.a.Sub(s.a)
+	tParam := cross(qp, q) / denom
+	uParam := cross(qp, r) / denom
+	if tParam < 0 || tParam > 1 || uParam < 0 || uParam > 1 {
+		return geom.XY{}, false
+	}
+	return geom.XY{X: s.a.X + tParam*r.X, Y: s.a.Y + tParam*r.Y}, true
+}
+
+func cross(a, b geom.XY) float64 {
+	return a.X*b.Y - a.Y*b.X
+}
+
+func buildHotPixelIndex(pixels []geom.XY) *rtree.RTree {
+	items := make([]rtree.BulkItem, len(pixels))
+	for i, p := range pixels {
+		items[i] = rtree.BulkItem{
+			Box:      rtree.Box{MinX: p.X, MinY: p.Y, MaxX: p.X, MaxY: p.Y},
+			RecordID: i,
+		}
+	}
+	return rtree.BulkLoad(items)
+}
+
+// segmentThroughPixel reports whether segment s passes through the
+// grid.Size-wide square centred at px, using the same successive
+// half-plane (Liang-Barsky) clipping technique as geom's clipSegmentToBox.
+func segmentThroughPixel(s segment, px geom.XY, grid SnapGrid) bool {
+	half := grid.Size / 2
+	dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+	tMin, tMax := 0.0, 1.0
+
+	clip := func(p, q float64) bool {
+		if p == 0 {
+			return q >= 0
+		}
+		t := q / p
+		if p < 0 {
+			if t > tMax {
+				return false
+			}
+			if t > tMin {
+				tMin = t
+			}
+		} else {
+			if t < tMin {
+				return false
+			}
+			if t < tMax {
+				tMax = t
+			}
+		}
+		return true
+	}
+
+	return clip(-dx, s.a.X-(px.X-half)) &&
+		clip(dx, (px.X+half)-s.a.X) &&
+		clip(-dy, s.a.Y-(px.Y-half)) &&
+		clip(dy, (px.Y+half)-s.a.Y)
+}
+
+// snapSegment finds every hot pixel (from pixels, via pixelIndex) that
+// segment s passes through, and returns them in order along s (deduped),
+// forming the vertex chain that s is split into.
+func snapSegment(s segment, grid SnapGrid, pixels []geom.XY, pixelIndex *rtree.RTree) []geom.XY {
+	half := grid.Size / 2
+	box := segmentBox(s)
+	query := rtree.Box{
+		MinX: box.MinX - half, MinY: box.MinY - half,
+		MaxX: box.MaxX + half, MaxY: box.MaxY + half,
+	}
+
+	dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+	lenSq := dx*dx + dy*dy
+
+	type hit struct {
+		t  float64
+		xy geom.XY
+	}
+	var hits []hit
+	pixelIndex.PrioritySearch(query, func(recordID int) error {
+		px := pixels[recordID]
+		if !segmentThroughPixel(s, px, grid) {
+			return nil
+		}
+		t := 0.0
+		if lenSq > 0 {
+			t = ((px.X-s.a.X)*dx + (px.Y-s.a.Y)*dy) / lenSq
+		}
+		hits = append(hits, hit{t, px})
+		return nil
+	})
+	sort.Slice(hits, func(i, j int) bool { return hits[i].t < hits[j].t })
+
+	chain := make([]geom.XY, 0, len(hits))
+	for _, h := range hits {
+		if len(chain) == 0 || chain[len(chain)-1] != h.xy {
+			chain = append(chain, h.xy)
+		}
+	}
+	return chain
+}
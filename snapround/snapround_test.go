@@ -0,0 +1,74 @@
+package snapround_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/peterstace/simplefeatures/geom"
+	"github.com/peterstace/simplefeatures/snapround"
+)
+
+func geomFromWKT(t *testing.T, wkt string) geom.Geometry {
+	t.Helper()
+	g, err := geom.UnmarshalWKT(strings.NewReader(wkt))
+	if err != nil {
+		t.Fatalf("could not unmarshal WKT:\n  wkt: %s\n  err: %v", wkt, err)
+	}
+	return g
+}
+
+func TestSnapRoundSnapsNearbyVertices(t *testing.T) {
+	// Two lines that cross near (5, 5), with endpoints perturbed by less
+	// than the grid size in every direction. After rounding onto a unit
+	// grid, both lines should end up passing through the same hot pixel
+	// at (5, 5).
+	g := geomFromWKT(t, "MULTILINESTRING((0.1 0.1,9.9 9.9),(0.2 9.8,9.8 0.2))")
+
+	result, hotPixels := snapround.SnapRound(g, snapround.SnapGrid{Size: 1})
+
+	if _, ok := hotPixels[geom.XY{X: 5, Y: 5}]; !ok {
+		t.Errorf("expected (5, 5) to be a hot pixel, got %v", hotPixels)
+	}
+
+	mls := result.AsMultiLineString()
+	if n := mls.NumLineStrings(); n == 0 {
+		t.Fatalf("SnapRound produced no output line strings")
+	}
+
+	// Every coordinate in the output must land exactly on the grid.
+	for i := 0; i < mls.NumLineStrings(); i++ {
+		seq := mls.LineStringN(i).Coordinates()
+		for j := 0; j < seq.Length(); j++ {
+			xy := seq.GetXY(j)
+			if xy.X != float64(int(xy.X)) || xy.Y != float64(int(xy.Y)) {
+				t.Errorf("coordinate %v isn't snapped onto the unit grid", xy)
+			}
+		}
+	}
+}
+
+func TestAsNoderFindsHotPixelsAcrossBothOperands(t *testing.T) {
+	// a and b each contribute one line, and their vertices near (5, 5)
+	// (one from each operand) both round onto that grid point, so the
+	// hot pixel set the Noder computes over both operands must contain it.
+	a := geomFromWKT(t, "LINESTRING(0 0,4.9 4.9)")
+	b := geomFromWKT(t, "LINESTRING(5.1 5.1,10 10)")
+
+	noder := snapround.AsNoder(snapround.SnapGrid{Size: 1})
+	hotPixels := noder(a, b)
+
+	if _, ok := hotPixels[geom.XY{X: 5, Y: 5}]; !ok {
+		t.Errorf("expected (5, 5) to be a hot pixel shared by both operands, got %v", hotPixels)
+	}
+}
+
+func TestSnapRoundEmptyInput(t *testing.T) {
+	g := geomFromWKT(t, "POINT EMPTY")
+	result, hotPixels := snapround.SnapRound(g, snapround.SnapGrid{Size: 1})
+	if hotPixels != nil {
+		t.Errorf("expected no hot pixels for an input with no segments, got %v", hotPixels)
+	}
+	if !result.EqualsExact(g) {
+		t.Errorf("expected the input to be returned unchanged, got %v", result.AsText())
+	}
+}
@@ -1,8 +1,8 @@
 package simplefeatures
 
 import (
-	"crypto/sha256"
-	"fmt"
+	"hash/maphash"
+	"math/big"
 )
 
 type XY struct {
@@ -42,28 +42,62 @@ func (w XY) Midpoint(o XY) XY {
 	return w.Add(o).Scale(half)
 }
 
-type xyHash [sha256.Size]byte
+var xySetHashSeed = maphash.MakeSeed()
 
-func (w XY) hash() xyHash {
-	h := sha256.New()
-	fmt.Fprintf(h, "%s,%s", w.X.val, w.Y.val)
-	var sum xyHash
-	h.Sum(sum[:0])
-	return sum
+// hash computes a fast, non-cryptographic hash of w's coordinates, for use
+// as an xySet bucket key. This used to go via fmt.Fprintf formatting each
+// Scalar to a decimal string and hashing that, which re-did the
+// big-decimal-to-string conversion Scalar's String method already pays for
+// elsewhere, for every single bucket lookup. Scalar stores its value as a
+// canonical (GCD-reduced) big.Rat, so hashing its numerator/denominator
+// bytes directly is both cheaper and collision-safe in the same way: two
+// Scalars compare Equals only if their reduced rationals match exactly.
+func (w XY) hash() uint64 {
+	var h maphash.Hash
+	h.SetSeed(xySetHashSeed)
+	writeCanonicalRatBytes(&h, w.X.AsRat())
+	writeCanonicalRatBytes(&h, w.Y.AsRat())
+	return h.Sum64()
 }
 
-type xySet map[xyHash]XY
+// writeCanonicalRatBytes writes the big-endian bytes of r's reduced
+// numerator and denominator into h, each followed by a zero byte to mark
+// where it ends. big.Rat always stores its numerator/denominator
+// pre-reduced by their GCD, so equal values write equal bytes. Bytes gives
+// the absolute value, so r and -r write the same bytes and share an xySet
+// bucket, where XY.Equals tells them apart.
+func writeCanonicalRatBytes(h *maphash.Hash, r *big.Rat) {
+	_, _ = h.Write(r.Num().Bytes())
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(r.Denom().Bytes())
+	_, _ = h.Write([]byte{0})
+}
+
+// xySet is a set of XY values. Membership is keyed by hash, but since
+// hash's 64 bits are short enough that two distinct XY values can
+// plausibly land in the same bucket (unlike the SHA-256 digest this
+// replaced, which was treated as collision-free), each bucket is a short
+// slice scanned with XY.Equals rather than a single map entry.
+type xySet struct {
+	buckets map[uint64][]XY
+}
 
 func newXYSet() xySet {
-	return make(map[xyHash]XY)
+	return xySet{buckets: make(map[uint64][]XY)}
 }
 
 func (s xySet) add(xy XY) {
-	s[xy.hash()] = xy
+	h := xy.hash()
+	for _, cand := range s.buckets[h] {
+		if cand.Equals(xy) {
+			return
+		}
+	}
+	s.buckets[h] = append(s.buckets[h], xy)
 }
 
 type xyxyHash struct {
-	a, b xyHash
+	a, b uint64
 }
 
 func hashXYXY(a, b XY) xyxyHash {
@@ -71,4 +105,4 @@ func hashXYXY(a, b XY) xyxyHash {
 		a.hash(),
 		b.hash(),
 	}
-}
\ No newline at end of file
+}